@@ -0,0 +1,414 @@
+package opt
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ interface {
+	sql.Scanner
+	driver.Valuer
+} = (*Array[any])(nil)
+
+// Array is a slice of options which can be scanned from and converted to
+// PostgreSQL array literals in the text format, e.g. `{1,NULL,3}`.
+//
+// NULL elements are mapped to [None] and every other element is scanned with [Opt.Scan],
+// so the same conversion rules apply.
+//
+// Elements of [time.Time] are scanned from the PostgreSQL timestamp, timestamptz and date text formats.
+//
+// Multidimensional arrays are represented by nesting:
+//
+//	var matrix opt.Array[opt.Array[int]] // {{1,2},{NULL,4}}
+type Array[T any] []Opt[T]
+
+// Scan implements the [sql.Scanner] interface.
+func (a *Array[T]) Scan(src any) error {
+	var literal string
+
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+
+		return nil
+	case string:
+		literal = src
+	case []byte:
+		literal = string(src)
+	default:
+		return fmt.Errorf("Array[T].Scan: unsupported source type %T", src)
+	}
+
+	elements, err := parseArray(literal)
+	if err != nil {
+		return fmt.Errorf("Array[T].Scan: %w", err)
+	}
+
+	array := make(Array[T], len(elements))
+
+	var zero T
+
+	for i, element := range elements {
+		if element.isNull {
+			array[i] = None[T]()
+
+			continue
+		}
+
+		src, err := element.source(zero)
+		if err != nil {
+			return fmt.Errorf("Array[T].Scan: element %d: %w", i, err)
+		}
+
+		if err := array[i].Scan(src); err != nil {
+			return fmt.Errorf("Array[T].Scan: element %d: %w", i, err)
+		}
+	}
+
+	*a = array
+
+	return nil
+}
+
+// Value implements the [driver.Valuer] interface.
+//
+// Nil array is converted to NULL, while an empty one is converted to `{}`.
+func (a Array[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	b, err := a.appendArray(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (a Array[T]) appendArray(b []byte) ([]byte, error) {
+	b = append(b, '{')
+
+	for i, element := range a {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		if !element.hasValue {
+			b = append(b, "NULL"...)
+
+			continue
+		}
+
+		// nested arrays are written as is, without quoting
+		if nested, ok := any(element.value).(arrayAppender); ok {
+			var err error
+
+			if b, err = nested.appendArray(b); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		value, err := element.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if b, err = appendArrayElement(b, value); err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+type arrayAppender interface {
+	appendArray(b []byte) ([]byte, error)
+}
+
+func appendArrayElement(b []byte, value driver.Value) ([]byte, error) {
+	switch value := value.(type) {
+	case nil:
+		return append(b, "NULL"...), nil
+	case int64:
+		return strconv.AppendInt(b, value, 10), nil
+	case float64:
+		return strconv.AppendFloat(b, value, 'g', -1, 64), nil
+	case bool:
+		return strconv.AppendBool(b, value), nil
+	case []byte:
+		// bytea hex format, backslash is escaped inside quotes
+		b = append(b, `"\\x`...)
+		b = hex.AppendEncode(b, value)
+
+		return append(b, '"'), nil
+	case string:
+		return appendArrayString(b, value), nil
+	case time.Time:
+		return appendArrayString(b, value.Format(time.RFC3339Nano)), nil
+	default:
+		if stringer, ok := value.(fmt.Stringer); ok {
+			return appendArrayString(b, stringer.String()), nil
+		}
+
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+func appendArrayString(b []byte, s string) []byte {
+	if !needsArrayQuotes(s) {
+		return append(b, s...)
+	}
+
+	b = append(b, '"')
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b = append(b, '\\')
+		}
+
+		b = append(b, s[i])
+	}
+
+	return append(b, '"')
+}
+
+func needsArrayQuotes(s string) bool {
+	if s == "" || strings.EqualFold(s, "NULL") {
+		return true
+	}
+
+	return strings.ContainsAny(s, "{}\",\\ \t\n\r\v\f")
+}
+
+type arrayElement struct {
+	raw    string
+	isNull bool
+}
+
+// arrayTimeLayouts are PostgreSQL timestamp, timestamptz and date text formats,
+// as well as RFC 3339 used by [Array.Value].
+var arrayTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+	time.RFC3339Nano,
+}
+
+// source returns the element as a value for the [Opt.Scan] of the given zero value type.
+// Byte slices are decoded from the bytea hex format and times are parsed from the timestamp text format.
+func (e arrayElement) source(zero any) (any, error) {
+	switch zero.(type) {
+	case []byte:
+		if hexData, ok := strings.CutPrefix(e.raw, `\x`); ok {
+			if decoded, err := hex.DecodeString(hexData); err == nil {
+				return decoded, nil
+			}
+		}
+	case time.Time:
+		for _, layout := range arrayTimeLayouts {
+			if t, err := time.Parse(layout, e.raw); err == nil {
+				return t, nil
+			}
+		}
+
+		return nil, fmt.Errorf("malformed timestamp %q", e.raw)
+	}
+
+	return e.raw, nil
+}
+
+// parseArray splits the top level of the array literal into elements.
+// Nested arrays are returned as raw literals to be parsed by the element scanner.
+func parseArray(literal string) ([]arrayElement, error) {
+	// skip optional dimension decoration, e.g. [1:3]={1,2,3}
+	if strings.HasPrefix(literal, "[") {
+		_, after, ok := strings.Cut(literal, "=")
+		if !ok {
+			return nil, errors.New("malformed dimension decoration")
+		}
+
+		literal = after
+	}
+
+	literal = strings.TrimSpace(literal)
+
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return nil, fmt.Errorf("malformed array literal %q", literal)
+	}
+
+	p := arrayParser{input: literal[1 : len(literal)-1]}
+
+	return p.parse()
+}
+
+type arrayParser struct {
+	input string
+	pos   int
+}
+
+func (p *arrayParser) parse() ([]arrayElement, error) {
+	p.skipSpaces()
+
+	if p.pos == len(p.input) {
+		return []arrayElement{}, nil
+	}
+
+	var elements []arrayElement
+
+	for {
+		element, err := p.element()
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, element)
+
+		p.skipSpaces()
+
+		if p.pos == len(p.input) {
+			return elements, nil
+		}
+
+		if p.input[p.pos] != ',' {
+			return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+		}
+
+		p.pos++
+		p.skipSpaces()
+	}
+}
+
+func (p *arrayParser) element() (arrayElement, error) {
+	if p.pos == len(p.input) {
+		return arrayElement{}, errors.New("unexpected end of array literal")
+	}
+
+	switch p.input[p.pos] {
+	case '"':
+		return p.quoted()
+	case '{':
+		return p.nested()
+	default:
+		return p.unquoted()
+	}
+}
+
+func (p *arrayParser) quoted() (arrayElement, error) {
+	var buf strings.Builder
+
+	for p.pos++; p.pos < len(p.input); p.pos++ {
+		switch c := p.input[p.pos]; c {
+		case '\\':
+			p.pos++
+
+			if p.pos == len(p.input) {
+				return arrayElement{}, errors.New("unterminated escape sequence")
+			}
+
+			buf.WriteByte(p.input[p.pos])
+		case '"':
+			p.pos++
+
+			return arrayElement{raw: buf.String()}, nil
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return arrayElement{}, errors.New("unterminated quoted element")
+}
+
+func (p *arrayParser) nested() (arrayElement, error) {
+	start := p.pos
+	depth := 0
+	inQuotes := false
+
+	for ; p.pos < len(p.input); p.pos++ {
+		switch p.input[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			inQuotes = !inQuotes
+		case '{':
+			if !inQuotes {
+				depth++
+			}
+		case '}':
+			if inQuotes {
+				continue
+			}
+
+			depth--
+
+			if depth == 0 {
+				p.pos++
+
+				return arrayElement{raw: p.input[start:p.pos]}, nil
+			}
+		}
+	}
+
+	return arrayElement{}, errors.New("unterminated nested array")
+}
+
+func (p *arrayParser) unquoted() (arrayElement, error) {
+	var buf bytes.Buffer
+
+	// escaped is the length of the element up to the last escaped character,
+	// which is kept even if it is a whitespace
+	escaped := -1
+
+	for ; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+
+		if c == ',' {
+			break
+		}
+
+		switch c {
+		case '{', '}', '"':
+			return arrayElement{}, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+		case '\\':
+			p.pos++
+
+			if p.pos == len(p.input) {
+				return arrayElement{}, errors.New("unterminated escape sequence")
+			}
+
+			buf.WriteByte(p.input[p.pos])
+			escaped = buf.Len()
+
+			continue
+		}
+
+		buf.WriteByte(c)
+	}
+
+	raw := buf.String()
+	keep := max(escaped, 0)
+	raw = raw[:keep] + strings.TrimRight(raw[keep:], " \t\n\r\v\f")
+
+	if raw == "" {
+		return arrayElement{}, errors.New("empty unquoted element")
+	}
+
+	return arrayElement{raw: raw, isNull: escaped < 0 && strings.EqualFold(raw, "NULL")}, nil
+}
+
+func (p *arrayParser) skipSpaces() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\n\r\v\f", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
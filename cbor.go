@@ -0,0 +1,23 @@
+package opt
+
+import (
+	"github.com/metafates/opt/cbor"
+)
+
+var _ interface {
+	cbor.Marshaler
+	cbor.Unmarshaler
+} = (*Opt[any])(nil)
+
+// MarshalCBOR implements [cbor.Marshaler] interface, so that other CBOR libraries can encode options.
+//
+// All three states are preserved: [Some] is encoded as its value,
+// explicit [None] as null and implicit [None] as undefined.
+func (o Opt[T]) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(o)
+}
+
+// UnmarshalCBOR implements [cbor.Unmarshaler] interface.
+func (o *Opt[T]) UnmarshalCBOR(data []byte) error {
+	return cbor.Unmarshal(data, o)
+}
@@ -0,0 +1,157 @@
+// Package cbor implements encoding and decoding of CBOR as defined in RFC 8949.
+//
+// Mapping between CBOR and Go values follows [encoding/json] conventions:
+//   - Structs are encoded as maps with field names as keys, which can be changed with `cbor:"name"` tags.
+//     Fields tagged with `cbor:"-"` are ignored and `cbor:",omitempty"` omits empty values
+//   - Byte slices are encoded as byte strings
+//   - Nil pointers, slices, maps and interfaces are encoded as null
+//   - [time.Time] is encoded as RFC 3339 string with tag 0
+//   - Types implementing [encoding.TextMarshaler], e.g. netip.Addr, are encoded as text strings
+//     and types implementing only [encoding.BinaryMarshaler], e.g. url.URL, as byte strings
+//   - [opt.Opt] preserves all three states: Some is encoded as its value,
+//     explicit None as null and implicit None as undefined
+//
+// Both null and undefined are decoded into zero values, unless the value implements [Unmarshaler] or is an option.
+// Types may customize their encoding by implementing [Marshaler] and [Unmarshaler].
+//
+// Integers and floats are always encoded in the shortest form (preferred serialization).
+// Use [EncOptions] with Deterministic set for the core deterministic encoding, which also sorts map keys.
+//
+// [opt.Opt]: https://pkg.go.dev/github.com/metafates/opt#Opt
+package cbor
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Marshaler is the interface implemented by types that can marshal themselves into a valid CBOR data item.
+type Marshaler interface {
+	MarshalCBOR() ([]byte, error)
+}
+
+// Unmarshaler is the interface implemented by types that can unmarshal a CBOR data item of themselves.
+//
+// The data is a single well-formed data item, including null and undefined.
+// UnmarshalCBOR must copy the data if it wishes to retain the data after returning.
+type Unmarshaler interface {
+	UnmarshalCBOR(data []byte) error
+}
+
+// Major types
+const (
+	majorUint   byte = 0
+	majorNegInt byte = 1
+	majorBytes  byte = 2
+	majorText   byte = 3
+	majorArray  byte = 4
+	majorMap    byte = 5
+	majorTag    byte = 6
+	majorSimple byte = 7
+)
+
+// Simple values and special additional information
+const (
+	simpleFalse     byte = 20
+	simpleTrue      byte = 21
+	simpleNull      byte = 22
+	simpleUndefined byte = 23
+	infoFloat16     byte = 25
+	infoFloat32     byte = 26
+	infoFloat64     byte = 27
+	infoIndefinite  byte = 31
+	breakCode       byte = 0xff
+)
+
+const (
+	tagDateTimeString uint64 = 0
+	tagEpochDateTime  uint64 = 1
+)
+
+const (
+	// Null is the encoded CBOR null data item.
+	Null = majorSimple<<5 | simpleNull
+
+	// Undefined is the encoded CBOR undefined data item.
+	Undefined = majorSimple<<5 | simpleUndefined
+)
+
+// EncOptions specifies encoding options.
+type EncOptions struct {
+	// Deterministic enables core deterministic encoding requirements (RFC 8949, section 4.2.1):
+	// map keys are sorted in the bytewise lexicographic order of their encoding
+	// and indefinite-length items produced by [Marshaler] implementations are converted to definite ones.
+	Deterministic bool
+}
+
+// Marshal returns the CBOR encoding of v with default options.
+func Marshal(v any) ([]byte, error) {
+	return EncOptions{}.Marshal(v)
+}
+
+// Marshal returns the CBOR encoding of v.
+func (o EncOptions) Marshal(v any) ([]byte, error) {
+	var e encoder
+
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	if o.Deterministic {
+		return canonicalize(e.buf)
+	}
+
+	return e.buf, nil
+}
+
+// Unmarshal parses the CBOR-encoded data and stores the result in the value pointed to by v.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cbor: Unmarshal(non-pointer %T)", v)
+	}
+
+	d := decoder{data: data}
+
+	if err := d.decode(rv.Elem()); err != nil {
+		return err
+	}
+
+	if d.pos != len(data) {
+		return &SyntaxError{Offset: d.pos, msg: "unexpected data after top-level item"}
+	}
+
+	return nil
+}
+
+// Valid reports whether data is a single well-formed CBOR data item.
+func Valid(data []byte) bool {
+	d := decoder{data: data}
+
+	return d.skip() == nil && d.pos == len(data)
+}
+
+// SyntaxError is a description of a malformed CBOR data.
+type SyntaxError struct {
+	// Offset is the byte offset after which the error occurred
+	Offset int
+
+	msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("cbor: %s at offset %d", e.msg, e.Offset)
+}
+
+// UnmarshalTypeError describes a CBOR value that was not appropriate for a value of a specific Go type.
+type UnmarshalTypeError struct {
+	// Value is the description of CBOR value, e.g. "byte string"
+	Value string
+
+	// Type is the Go type it could not be assigned to
+	Type reflect.Type
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("cbor: cannot unmarshal %s into Go value of type %s", e.Value, e.Type)
+}
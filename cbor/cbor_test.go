@@ -0,0 +1,224 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
+
+// Examples from RFC 8949, Appendix A
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		value any
+		want  string
+	}{
+		{0, "00"},
+		{1, "01"},
+		{10, "0a"},
+		{23, "17"},
+		{24, "1818"},
+		{100, "1864"},
+		{1000, "1903e8"},
+		{1000000, "1a000f4240"},
+		{uint64(1000000000000), "1b000000e8d4a51000"},
+		{uint64(18446744073709551615), "1bffffffffffffffff"},
+		{-1, "20"},
+		{-10, "29"},
+		{-100, "3863"},
+		{-1000, "3903e7"},
+		{0.0, "f90000"},
+		{math.Copysign(0, -1), "f98000"},
+		{1.0, "f93c00"},
+		{1.1, "fb3ff199999999999a"},
+		{1.5, "f93e00"},
+		{65504.0, "f97bff"},
+		{100000.0, "fa47c35000"},
+		{3.4028234663852886e+38, "fa7f7fffff"},
+		{1.0e+300, "fb7e37e43c8800759c"},
+		{5.960464477539063e-8, "f90001"},
+		{0.00006103515625, "f90400"},
+		{-4.0, "f9c400"},
+		{-4.1, "fbc010666666666666"},
+		{math.Inf(1), "f97c00"},
+		{math.NaN(), "f97e00"},
+		{math.Inf(-1), "f9fc00"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{[]byte{}, "40"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"a", "6161"},
+		{"IETF", "6449455446"},
+		{"\"\\", "62225c"},
+		{"ü", "62c3bc"},
+		{"水", "63e6b0b4"},
+		{[]int{}, "80"},
+		{[]int{1, 2, 3}, "83010203"},
+		{[]any{1, []int{2, 3}, []int{4, 5}}, "8301820203820405"},
+		{map[string]int{}, "a0"},
+		{map[string]string{"a": "A"}, "a161616141"},
+		{time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), "c074323031332d30332d32315432303a30343a30305a"},
+	}
+
+	for _, tc := range testCases {
+		got, err := Marshal(tc.value)
+		require.NoError(t, err, "%#v", tc.value)
+		require.Equal(t, tc.want, hex.EncodeToString(got), "%#v", tc.value)
+	}
+}
+
+func TestEncOptions_Marshal(t *testing.T) {
+	value := map[any]any{
+		"aa":  1,
+		"b":   2,
+		10:    3,
+		-1:    4,
+		false: 5,
+	}
+
+	got, err := EncOptions{Deterministic: true}.Marshal(value)
+	require.NoError(t, err)
+
+	// keys sorted by their encoding: 0a, 20, 6162, 626161, f4
+	require.Equal(t, "a50a03200461620262616101f405", hex.EncodeToString(got))
+
+	t.Run("indefinite from marshaler", func(t *testing.T) {
+		got, err := EncOptions{Deterministic: true}.Marshal(rawMarshaler(mustHex(t, "bf616202616101ff")))
+		require.NoError(t, err)
+		require.Equal(t, "a2616101616202", hex.EncodeToString(got))
+	})
+}
+
+type rawMarshaler []byte
+
+func (r rawMarshaler) MarshalCBOR() ([]byte, error) {
+	return r, nil
+}
+
+type Inner struct {
+	Value int `cbor:"v"`
+}
+
+type Item struct {
+	Inner
+
+	Name    string    `cbor:"name"`
+	Tags    []string  `cbor:"tags,omitempty"`
+	Data    []byte    `cbor:"data"`
+	Score   float64   `cbor:"score"`
+	At      time.Time `cbor:"at"`
+	Ptr     *int      `cbor:"ptr"`
+	Ignored string    `cbor:"-"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	n := 5
+
+	item := Item{
+		Inner: Inner{Value: 42},
+		Name:  "x",
+		Data:  []byte{1, 2},
+		Score: 1.25,
+		At:    time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Ptr:   &n,
+	}
+
+	data, err := Marshal(item)
+	require.NoError(t, err)
+
+	var decoded Item
+
+	require.NoError(t, Unmarshal(data, &decoded))
+	require.Equal(t, item, decoded)
+
+	var generic any
+
+	require.NoError(t, Unmarshal(data, &generic))
+	require.Equal(t, map[string]any{
+		"v":     int64(42),
+		"name":  "x",
+		"data":  []byte{1, 2},
+		"score": 1.25,
+		"at":    item.At,
+		"ptr":   int64(5),
+	}, generic)
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("indefinite", func(t *testing.T) {
+		var s string
+		require.NoError(t, Unmarshal(mustHex(t, "7f657374726561646d696e67ff"), &s))
+		require.Equal(t, "streaming", s)
+
+		var list []any
+		require.NoError(t, Unmarshal(mustHex(t, "9f018202039f0405ffff"), &list))
+		require.Equal(t, []any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}, list)
+
+		var m map[string]any
+		require.NoError(t, Unmarshal(mustHex(t, "bf61610161629f0203ffff"), &m))
+		require.Equal(t, map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}, m)
+	})
+
+	t.Run("epoch time", func(t *testing.T) {
+		var at time.Time
+		require.NoError(t, Unmarshal(mustHex(t, "c11a514b67b0"), &at))
+		require.True(t, at.Equal(time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)))
+	})
+
+	t.Run("null and undefined", func(t *testing.T) {
+		n := 1
+		ptr := &n
+
+		require.NoError(t, Unmarshal([]byte{Null}, &ptr))
+		require.Nil(t, ptr)
+
+		x := 5
+		require.NoError(t, Unmarshal([]byte{Undefined}, &x))
+		require.Zero(t, x)
+	})
+
+	t.Run("floats", func(t *testing.T) {
+		var f float64
+		require.NoError(t, Unmarshal(mustHex(t, "f90001"), &f))
+		require.Equal(t, 5.960464477539063e-8, f)
+
+		require.NoError(t, Unmarshal(mustHex(t, "f97bff"), &f))
+		require.Equal(t, 65504.0, f)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var n int8
+
+		var typeErr *UnmarshalTypeError
+		require.ErrorAs(t, Unmarshal(mustHex(t, "190100"), &n), &typeErr)
+
+		var s string
+		require.ErrorAs(t, Unmarshal(mustHex(t, "01"), &s), &typeErr)
+
+		var syntaxErr *SyntaxError
+		require.ErrorAs(t, Unmarshal(mustHex(t, "62c3"), &s), &syntaxErr)
+		require.ErrorAs(t, Unmarshal(mustHex(t, "0101"), &n), &syntaxErr)
+		require.ErrorAs(t, Unmarshal(mustHex(t, "1c"), &n), &syntaxErr)
+
+		require.Error(t, Unmarshal(mustHex(t, "01"), n))
+	})
+}
+
+func TestValid(t *testing.T) {
+	require.True(t, Valid(mustHex(t, "8301820203820405")))
+	require.False(t, Valid(mustHex(t, "830102")))
+	require.False(t, Valid(mustHex(t, "ff")))
+}
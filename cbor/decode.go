@@ -0,0 +1,902 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/structfields"
+)
+
+// maxDepth limits nesting of decoded items to avoid stack exhaustion on malicious input.
+const maxDepth = 10000
+
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+type head struct {
+	major byte
+	info  byte
+	arg   uint64
+}
+
+func (h head) indefinite() bool {
+	return h.info == infoIndefinite
+}
+
+func (d *decoder) syntaxError(msg string) error {
+	return &SyntaxError{Offset: d.pos, msg: msg}
+}
+
+func (d *decoder) peek() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, d.syntaxError("unexpected end of data")
+	}
+
+	return d.data[d.pos], nil
+}
+
+func (d *decoder) readHead() (head, error) {
+	initial, err := d.peek()
+	if err != nil {
+		return head{}, err
+	}
+
+	d.pos++
+
+	h := head{major: initial >> 5, info: initial & 0x1f}
+
+	var size int
+
+	switch {
+	case h.info < 24:
+		h.arg = uint64(h.info)
+
+		return h, nil
+	case h.info == 24:
+		size = 1
+	case h.info == 25:
+		size = 2
+	case h.info == 26:
+		size = 4
+	case h.info == 27:
+		size = 8
+	case h.info == infoIndefinite && h.major >= majorBytes && h.major <= majorMap:
+		return h, nil
+	case h.info == infoIndefinite && h.major == majorSimple:
+		return head{}, d.syntaxError("unexpected break")
+	default:
+		return head{}, d.syntaxError("reserved additional information")
+	}
+
+	if len(d.data)-d.pos < size {
+		return head{}, d.syntaxError("unexpected end of data")
+	}
+
+	raw := d.data[d.pos : d.pos+size]
+	d.pos += size
+
+	switch size {
+	case 1:
+		h.arg = uint64(raw[0])
+	case 2:
+		h.arg = uint64(binary.BigEndian.Uint16(raw))
+	case 4:
+		h.arg = uint64(binary.BigEndian.Uint32(raw))
+	case 8:
+		h.arg = binary.BigEndian.Uint64(raw)
+	}
+
+	if h.major == majorSimple && h.info == 24 && h.arg < 32 {
+		return head{}, d.syntaxError("invalid simple value")
+	}
+
+	return h, nil
+}
+
+// atBreak consumes the break code if it is next.
+func (d *decoder) atBreak() (bool, error) {
+	b, err := d.peek()
+	if err != nil {
+		return false, err
+	}
+
+	if b == breakCode {
+		d.pos++
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// skip skips a single data item validating that it is well-formed.
+func (d *decoder) skip() error {
+	if d.depth++; d.depth > maxDepth {
+		return d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	h, err := d.readHead()
+	if err != nil {
+		return err
+	}
+
+	switch h.major {
+	case majorBytes, majorText:
+		_, err := d.readString(h)
+
+		return err
+	case majorArray, majorMap:
+		items := 1
+		if h.major == majorMap {
+			items = 2
+		}
+
+		if h.indefinite() {
+			for {
+				done, err := d.atBreak()
+				if err != nil {
+					return err
+				}
+
+				if done {
+					return nil
+				}
+
+				for range items {
+					if err := d.skip(); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for i := uint64(0); i < h.arg; i++ {
+			for range items {
+				if err := d.skip(); err != nil {
+					return err
+				}
+			}
+		}
+	case majorTag:
+		return d.skip()
+	}
+
+	return nil
+}
+
+// readString reads the content of byte or text string, concatenating indefinite-length chunks.
+func (d *decoder) readString(h head) ([]byte, error) {
+	if !h.indefinite() {
+		if h.arg > uint64(len(d.data)-d.pos) {
+			return nil, d.syntaxError("unexpected end of data")
+		}
+
+		s := d.data[d.pos : d.pos+int(h.arg)]
+		d.pos += int(h.arg)
+
+		return s, nil
+	}
+
+	var buf []byte
+
+	for {
+		done, err := d.atBreak()
+		if err != nil {
+			return nil, err
+		}
+
+		if done {
+			return buf, nil
+		}
+
+		chunk, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+
+		if chunk.major != h.major || chunk.indefinite() {
+			return nil, d.syntaxError("invalid indefinite-length string chunk")
+		}
+
+		s, err := d.readString(chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, s...)
+	}
+}
+
+func (d *decoder) readFloat(h head) float64 {
+	switch h.info {
+	case infoFloat16:
+		return float16ToFloat64(uint16(h.arg))
+	case infoFloat32:
+		return float64(math.Float32frombits(uint32(h.arg)))
+	default:
+		return math.Float64frombits(h.arg)
+	}
+}
+
+func isFloat(h head) bool {
+	return h.major == majorSimple && h.info >= infoFloat16 && h.info <= infoFloat64
+}
+
+func describe(h head) string {
+	switch h.major {
+	case majorUint, majorNegInt:
+		return "integer"
+	case majorBytes:
+		return "byte string"
+	case majorText:
+		return "text string"
+	case majorArray:
+		return "array"
+	case majorMap:
+		return "map"
+	case majorTag:
+		return "tag"
+	}
+
+	switch {
+	case isFloat(h):
+		return "float"
+	case h.info == simpleFalse || h.info == simpleTrue:
+		return "bool"
+	case h.info == simpleNull:
+		return "null"
+	case h.info == simpleUndefined:
+		return "undefined"
+	default:
+		return "simple value"
+	}
+}
+
+func (d *decoder) decode(v reflect.Value) error {
+	start := d.pos
+
+	if optreflect.Is(v.Type()) {
+		return d.decodeOption(v)
+	}
+
+	if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(unmarshalerType) {
+		if err := d.skip(); err != nil {
+			return err
+		}
+
+		return v.Addr().Interface().(Unmarshaler).UnmarshalCBOR(d.data[start:d.pos])
+	}
+
+	if d.depth++; d.depth > maxDepth {
+		return d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	initial, err := d.peek()
+	if err != nil {
+		return err
+	}
+
+	// null and undefined
+	if initial == Null || initial == Undefined {
+		d.pos++
+
+		v.SetZero()
+
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return d.decode(v.Elem())
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		value, err := d.decodeAny()
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+
+		return nil
+	}
+
+	h, err := d.readHead()
+	if err != nil {
+		return err
+	}
+
+	if h.major == majorTag {
+		return d.decodeTagged(h.arg, v)
+	}
+
+	if (h.major == majorText || h.major == majorBytes) && v.Type() != timeType {
+		if ok, err := d.decodeText(h, v); ok {
+			return err
+		}
+	}
+
+	if v.Type() == timeType {
+		return d.decodeTime(h, v)
+	}
+
+	typeError := &UnmarshalTypeError{Value: describe(h), Type: v.Type()}
+
+	switch h.major {
+	case majorUint, majorNegInt:
+		return d.decodeInt(h, v, typeError)
+	case majorBytes, majorText:
+		s, err := d.readString(h)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(string(s))
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetBytes(bytes.Clone(s))
+		case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetZero()
+			reflect.Copy(v, reflect.ValueOf(s))
+		default:
+			return typeError
+		}
+
+		return nil
+	case majorArray:
+		return d.decodeArray(h, v, typeError)
+	case majorMap:
+		return d.decodeMap(h, v, typeError)
+	}
+
+	switch {
+	case isFloat(h):
+		if v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64 {
+			return typeError
+		}
+
+		v.SetFloat(d.readFloat(h))
+	case h.info == simpleFalse || h.info == simpleTrue:
+		if v.Kind() != reflect.Bool {
+			return typeError
+		}
+
+		v.SetBool(h.info == simpleTrue)
+	default:
+		return typeError
+	}
+
+	return nil
+}
+
+// decodeOption decodes undefined into implicit None, null into explicit None and anything else into Some.
+func (d *decoder) decodeOption(v reflect.Value) error {
+	initial, err := d.peek()
+	if err != nil {
+		return err
+	}
+
+	switch initial {
+	case Undefined:
+		d.pos++
+
+		v.SetZero()
+	case Null:
+		d.pos++
+
+		optreflect.SetNone(v)
+	default:
+		value := reflect.New(optreflect.Elem(v.Type())).Elem()
+
+		if err := d.decode(value); err != nil {
+			return err
+		}
+
+		optreflect.SetSome(v, value)
+	}
+
+	return nil
+}
+
+// decodeText decodes the text or byte string with [encoding.TextUnmarshaler] or [encoding.BinaryUnmarshaler]
+// and reports whether the value implements either of them.
+func (d *decoder) decodeText(h head, v reflect.Value) (bool, error) {
+	if !v.CanAddr() {
+		return false, nil
+	}
+
+	ptr := v.Addr().Interface()
+
+	textUnmarshaler, isText := ptr.(encoding.TextUnmarshaler)
+	binaryUnmarshaler, isBinary := ptr.(encoding.BinaryUnmarshaler)
+
+	if !isText && !isBinary {
+		return false, nil
+	}
+
+	s, err := d.readString(h)
+	if err != nil {
+		return true, err
+	}
+
+	if isText && (h.major == majorText || !isBinary) {
+		return true, textUnmarshaler.UnmarshalText(bytes.Clone(s))
+	}
+
+	return true, binaryUnmarshaler.UnmarshalBinary(bytes.Clone(s))
+}
+
+func (d *decoder) decodeInt(h head, v reflect.Value, typeError error) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if h.arg > math.MaxInt64 {
+			return typeError
+		}
+
+		n := int64(h.arg)
+		if h.major == majorNegInt {
+			n = -1 - n
+		}
+
+		if v.OverflowInt(n) {
+			return typeError
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if h.major == majorNegInt || v.OverflowUint(h.arg) {
+			return typeError
+		}
+
+		v.SetUint(h.arg)
+	case reflect.Float32, reflect.Float64:
+		f := float64(h.arg)
+		if h.major == majorNegInt {
+			f = -1 - f
+		}
+
+		v.SetFloat(f)
+	default:
+		return typeError
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeArray(h head, v reflect.Value, typeError error) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	case reflect.Array:
+		v.SetZero()
+	default:
+		return typeError
+	}
+
+	for i := 0; ; i++ {
+		more, err := d.more(h, uint64(i))
+		if err != nil {
+			return err
+		}
+
+		if !more {
+			return nil
+		}
+
+		switch {
+		case v.Kind() == reflect.Slice:
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		case i < v.Len():
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		default:
+			if err := d.skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *decoder) decodeMap(h head, v reflect.Value, typeError error) error {
+	var fields map[string]structfields.Field
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+	case reflect.Struct:
+		fields = make(map[string]structfields.Field)
+
+		for _, f := range structFields(v.Type()) {
+			fields[f.Name] = f
+		}
+	default:
+		return typeError
+	}
+
+	for i := uint64(0); ; i++ {
+		more, err := d.more(h, i)
+		if err != nil {
+			return err
+		}
+
+		if !more {
+			return nil
+		}
+
+		if v.Kind() == reflect.Map {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decode(key); err != nil {
+				return err
+			}
+
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(value); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(key, value)
+
+			continue
+		}
+
+		var name string
+		if err := d.decode(reflect.ValueOf(&name).Elem()); err != nil {
+			return err
+		}
+
+		f, ok := fields[name]
+		if !ok {
+			if err := d.skip(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fieldValue, ok := structfields.ByIndexAlloc(v, f.Index)
+		if !ok {
+			return &UnmarshalTypeError{Value: "map", Type: fieldValue.Type()}
+		}
+
+		if err := d.decode(fieldValue); err != nil {
+			return err
+		}
+	}
+}
+
+// more reports whether there are more items in the array or map after reading i items.
+func (d *decoder) more(h head, i uint64) (bool, error) {
+	if !h.indefinite() {
+		return i < h.arg, nil
+	}
+
+	done, err := d.atBreak()
+
+	return !done, err
+}
+
+func (d *decoder) decodeTagged(tag uint64, v reflect.Value) error {
+	if v.Type() != timeType || (tag != tagDateTimeString && tag != tagEpochDateTime) {
+		// unknown tags are ignored
+		return d.decode(v)
+	}
+
+	h, err := d.readHead()
+	if err != nil {
+		return err
+	}
+
+	return d.decodeTime(h, v)
+}
+
+func (d *decoder) decodeTime(h head, v reflect.Value) error {
+	var t time.Time
+
+	switch {
+	case h.major == majorText:
+		s, err := d.readString(h)
+		if err != nil {
+			return err
+		}
+
+		if t, err = time.Parse(time.RFC3339Nano, string(s)); err != nil {
+			return err
+		}
+	case h.major == majorUint:
+		t = time.Unix(int64(h.arg), 0)
+	case h.major == majorNegInt:
+		t = time.Unix(-1-int64(h.arg), 0)
+	case isFloat(h):
+		sec, frac := math.Modf(d.readFloat(h))
+		t = time.Unix(int64(sec), int64(frac*1e9))
+	default:
+		return &UnmarshalTypeError{Value: describe(h), Type: v.Type()}
+	}
+
+	v.Set(reflect.ValueOf(t))
+
+	return nil
+}
+
+// decodeAny decodes the next item into a generic Go value:
+//   - integers into int64, or uint64 if it does not fit
+//   - floats into float64
+//   - byte strings into []byte and text strings into string
+//   - arrays into []any
+//   - maps into map[string]any if all the keys are strings, map[any]any otherwise
+//   - tags 0 and 1 into time.Time, other tags are ignored
+//   - null and undefined into nil
+func (d *decoder) decodeAny() (any, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	h, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch h.major {
+	case majorUint:
+		if h.arg > math.MaxInt64 {
+			return h.arg, nil
+		}
+
+		return int64(h.arg), nil
+	case majorNegInt:
+		if h.arg > math.MaxInt64 {
+			return nil, &UnmarshalTypeError{Value: "integer", Type: reflect.TypeFor[int64]()}
+		}
+
+		return -1 - int64(h.arg), nil
+	case majorBytes:
+		s, err := d.readString(h)
+
+		return bytes.Clone(s), err
+	case majorText:
+		s, err := d.readString(h)
+
+		return string(s), err
+	case majorArray:
+		items := []any{}
+
+		for i := uint64(0); ; i++ {
+			more, err := d.more(h, i)
+			if err != nil || !more {
+				return items, err
+			}
+
+			item, err := d.decodeAny()
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+	case majorMap:
+		return d.decodeAnyMap(h)
+	case majorTag:
+		if h.arg == tagDateTimeString || h.arg == tagEpochDateTime {
+			var t time.Time
+
+			if err := d.decode(reflect.ValueOf(&t).Elem()); err != nil {
+				return nil, err
+			}
+
+			return t, nil
+		}
+
+		return d.decodeAny()
+	}
+
+	switch {
+	case isFloat(h):
+		return d.readFloat(h), nil
+	case h.info == simpleFalse || h.info == simpleTrue:
+		return h.info == simpleTrue, nil
+	case h.info == simpleNull || h.info == simpleUndefined:
+		return nil, nil
+	default:
+		return nil, &UnmarshalTypeError{Value: describe(h), Type: reflect.TypeFor[any]()}
+	}
+}
+
+func (d *decoder) decodeAnyMap(h head) (any, error) {
+	var (
+		keys   []any
+		values []any
+	)
+
+	stringKeys := true
+
+	for i := uint64(0); ; i++ {
+		more, err := d.more(h, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if !more {
+			break
+		}
+
+		key, err := d.decodeAny()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decodeAny()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := key.(string); !ok {
+			stringKeys = false
+		}
+
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, &UnmarshalTypeError{Value: "map key", Type: reflect.TypeOf(key)}
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	if stringKeys {
+		m := make(map[string]any, len(keys))
+
+		for i, key := range keys {
+			m[key.(string)] = values[i]
+		}
+
+		return m, nil
+	}
+
+	m := make(map[any]any, len(keys))
+
+	for i, key := range keys {
+		m[key] = values[i]
+	}
+
+	return m, nil
+}
+
+// canonicalize re-encodes a single data item according to the core deterministic encoding requirements.
+func canonicalize(data []byte) ([]byte, error) {
+	d := decoder{data: data}
+
+	out, err := d.canonical(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(data) {
+		return nil, d.syntaxError("unexpected data after top-level item")
+	}
+
+	return out, nil
+}
+
+func (d *decoder) canonical(out []byte) ([]byte, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	h, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch h.major {
+	case majorUint, majorNegInt:
+		return appendHead(out, h.major, h.arg), nil
+	case majorBytes, majorText:
+		s, err := d.readString(h)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(appendHead(out, h.major, uint64(len(s))), s...), nil
+	case majorArray:
+		var items []byte
+
+		n := uint64(0)
+
+		for ; ; n++ {
+			more, err := d.more(h, n)
+			if err != nil {
+				return nil, err
+			}
+
+			if !more {
+				break
+			}
+
+			if items, err = d.canonical(items); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(appendHead(out, majorArray, n), items...), nil
+	case majorMap:
+		var entries [][2][]byte
+
+		for i := uint64(0); ; i++ {
+			more, err := d.more(h, i)
+			if err != nil {
+				return nil, err
+			}
+
+			if !more {
+				break
+			}
+
+			key, err := d.canonical(nil)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := d.canonical(nil)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, [2][]byte{key, value})
+		}
+
+		slices.SortFunc(entries, func(a, b [2][]byte) int {
+			return bytes.Compare(a[0], b[0])
+		})
+
+		out = appendHead(out, majorMap, uint64(len(entries)))
+
+		for _, entry := range entries {
+			out = append(out, entry[0]...)
+			out = append(out, entry[1]...)
+		}
+
+		return out, nil
+	case majorTag:
+		return d.canonical(appendHead(out, majorTag, h.arg))
+	}
+
+	if isFloat(h) {
+		e := encoder{buf: out}
+		e.float(d.readFloat(h))
+
+		return e.buf, nil
+	}
+
+	return appendHead(out, majorSimple, h.arg), nil
+}
@@ -0,0 +1,390 @@
+package cbor
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/structfields"
+)
+
+var (
+	marshalerType         = reflect.TypeFor[Marshaler]()
+	unmarshalerType       = reflect.TypeFor[Unmarshaler]()
+	textUnmarshalerType   = reflect.TypeFor[encoding.TextUnmarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+	timeType              = reflect.TypeFor[time.Time]()
+)
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, Null)
+
+		return nil
+	}
+
+	if optreflect.Is(v.Type()) {
+		return e.option(v)
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			e.buf = append(e.buf, Null)
+
+			return nil
+		}
+
+		data, err := v.Interface().(Marshaler).MarshalCBOR()
+		if err != nil {
+			return fmt.Errorf("cbor: error calling MarshalCBOR for type %s: %w", v.Type(), err)
+		}
+
+		if !Valid(data) {
+			return fmt.Errorf("cbor: MarshalCBOR for type %s returned malformed data", v.Type())
+		}
+
+		e.buf = append(e.buf, data...)
+
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+
+		e.head(majorTag, tagDateTimeString)
+		e.text(t.Format(time.RFC3339Nano))
+
+		return nil
+	}
+
+	// types keeping their state in unexported fields, e.g. netip.Addr, are encoded as strings
+	if m, ok := implementation[encoding.TextMarshaler](v); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return fmt.Errorf("cbor: error calling MarshalText for type %s: %w", v.Type(), err)
+		}
+
+		e.head(majorText, uint64(len(text)))
+		e.buf = append(e.buf, text...)
+
+		return nil
+	}
+
+	if m, ok := implementation[encoding.BinaryMarshaler](v); ok {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("cbor: error calling MarshalBinary for type %s: %w", v.Type(), err)
+		}
+
+		e.head(majorBytes, uint64(len(data)))
+		e.buf = append(e.buf, data...)
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, majorSimple<<5|simpleTrue)
+		} else {
+			e.buf = append(e.buf, majorSimple<<5|simpleFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.head(majorUint, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.float(v.Float())
+	case reflect.String:
+		e.text(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, Null)
+
+			return nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.head(majorBytes, uint64(v.Len()))
+			e.buf = append(e.buf, v.Bytes()...)
+
+			return nil
+		}
+
+		return e.array(v)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.head(majorBytes, uint64(v.Len()))
+
+			for i := 0; i < v.Len(); i++ {
+				e.buf = append(e.buf, byte(v.Index(i).Uint()))
+			}
+
+			return nil
+		}
+
+		return e.array(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf = append(e.buf, Null)
+
+			return nil
+		}
+
+		e.head(majorMap, uint64(v.Len()))
+
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := e.encode(iter.Key()); err != nil {
+				return err
+			}
+
+			if err := e.encode(iter.Value()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return e.structure(v)
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, Null)
+
+			return nil
+		}
+
+		return e.encode(v.Elem())
+	default:
+		return fmt.Errorf("cbor: unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// option encodes all three states of the option: Some as its value,
+// explicit None as null and implicit None as undefined.
+func (e *encoder) option(v reflect.Value) error {
+	if value, ok := optreflect.Get(v); ok {
+		return e.encode(value)
+	}
+
+	if optreflect.IsExplicit(v) {
+		e.buf = append(e.buf, Null)
+	} else {
+		e.buf = append(e.buf, Undefined)
+	}
+
+	return nil
+}
+
+func (e *encoder) array(v reflect.Value) error {
+	e.head(majorArray, uint64(v.Len()))
+
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) structure(v reflect.Value) error {
+	fields := structFields(v.Type())
+
+	type entry struct {
+		name  string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, len(fields))
+
+	for _, f := range fields {
+		value, err := v.FieldByIndexErr(f.Index)
+		if err != nil {
+			// nil embedded pointer
+			continue
+		}
+
+		if f.OmitEmpty && value.IsZero() {
+			continue
+		}
+
+		entries = append(entries, entry{name: f.Name, value: value})
+	}
+
+	e.head(majorMap, uint64(len(entries)))
+
+	for _, entry := range entries {
+		e.text(entry.name)
+
+		if err := e.encode(entry.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) int(n int64) {
+	if n < 0 {
+		// -1 - n without overflow
+		e.head(majorNegInt, uint64(^n))
+
+		return
+	}
+
+	e.head(majorUint, uint64(n))
+}
+
+func (e *encoder) text(s string) {
+	e.head(majorText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// float encodes f in the shortest form which preserves its value.
+func (e *encoder) float(f float64) {
+	if math.IsNaN(f) {
+		e.buf = append(e.buf, majorSimple<<5|infoFloat16, 0x7e, 0x00)
+
+		return
+	}
+
+	if f32 := float32(f); float64(f32) == f {
+		if f16, ok := float16Bits(f32); ok {
+			e.buf = append(e.buf, majorSimple<<5|infoFloat16)
+			e.buf = binary.BigEndian.AppendUint16(e.buf, f16)
+
+			return
+		}
+
+		e.buf = append(e.buf, majorSimple<<5|infoFloat32)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(f32))
+
+		return
+	}
+
+	e.buf = append(e.buf, majorSimple<<5|infoFloat64)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(f))
+}
+
+func (e *encoder) head(major byte, arg uint64) {
+	e.buf = appendHead(e.buf, major, arg)
+}
+
+func appendHead(b []byte, major byte, arg uint64) []byte {
+	major <<= 5
+
+	switch {
+	case arg < 24:
+		return append(b, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), arg)
+	}
+}
+
+// float16Bits returns IEEE 754 half-precision bits of f if it can be represented exactly.
+func float16Bits(f float32) (uint16, bool) {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23&0xff) - 127
+	mant := bits & 0x7fffff
+
+	switch {
+	case exp == 128:
+		// infinity, NaN is handled by the caller
+		if mant == 0 {
+			return sign | 0x7c00, true
+		}
+
+		return 0, false
+	case exp == -127 && mant == 0:
+		return sign, true
+	case exp >= -14 && exp <= 15:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+
+		return sign | uint16(exp+15)<<10 | uint16(mant>>13), true
+	case exp >= -24 && exp < -14:
+		// subnormal half
+		shift := uint(-exp - 1)
+		full := mant | 0x800000
+
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+
+		return sign | uint16(full>>shift), true
+	default:
+		return 0, false
+	}
+}
+
+func float16ToFloat64(bits uint16) float64 {
+	sign := 1.0
+	if bits&0x8000 != 0 {
+		sign = -1
+	}
+
+	exp := int(bits >> 10 & 0x1f)
+	mant := float64(bits & 0x3ff)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			return math.Inf(int(sign))
+		}
+
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(mant+1024, exp-25)
+	}
+}
+
+// implementation returns the value as the interface I, using the pointer to addressable values if needed.
+// Nil pointers are not returned, so that they are encoded as null.
+func implementation[I any](v reflect.Value) (I, bool) {
+	t := reflect.TypeFor[I]()
+
+	switch {
+	case v.Type().Implements(t):
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			break
+		}
+
+		return v.Interface().(I), true
+	case v.CanAddr() && reflect.PointerTo(v.Type()).Implements(t):
+		return v.Addr().Interface().(I), true
+	}
+
+	var zero I
+
+	return zero, false
+}
+
+func structFields(t reflect.Type) []structfields.Field {
+	return structfields.Of(t, "cbor", func(t reflect.Type) bool {
+		ptr := reflect.PointerTo(t)
+
+		return t == timeType ||
+			ptr.Implements(unmarshalerType) ||
+			ptr.Implements(textUnmarshalerType) ||
+			ptr.Implements(binaryUnmarshalerType)
+	})
+}
@@ -0,0 +1,133 @@
+package cbor_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/metafates/opt/cbor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOption(t *testing.T) {
+	type Patch struct {
+		Name  opt.Opt[string]
+		Age   opt.Opt[int]
+		Email opt.Opt[string]
+		Inner opt.Opt[opt.Opt[int]]
+	}
+
+	patch := Patch{Name: opt.Some("bob"), Age: opt.None[int](), Inner: opt.Some(opt.Some(1))}
+
+	data, err := cbor.Marshal(patch)
+	require.NoError(t, err)
+
+	var decoded Patch
+
+	require.NoError(t, cbor.Unmarshal(data, &decoded))
+	require.Equal(t, patch, decoded)
+	require.True(t, decoded.Age.IsExplicit())
+	require.False(t, decoded.Email.IsExplicit())
+
+	t.Run("states", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			option opt.Opt[int]
+			want   string
+		}{
+			{name: "some", option: opt.Some(1), want: "01"},
+			{name: "explicit none", option: opt.None[int](), want: "f6"},
+			{name: "implicit none", option: opt.Opt[int]{}, want: "f7"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				data, err := cbor.Marshal(tc.option)
+				require.NoError(t, err)
+				require.Equal(t, tc.want, hex.EncodeToString(data))
+
+				var decoded opt.Opt[int]
+
+				require.NoError(t, cbor.Unmarshal(data, &decoded))
+				require.Equal(t, tc.option, decoded)
+			})
+		}
+	})
+
+	t.Run("map values", func(t *testing.T) {
+		m := map[string]opt.Opt[int]{"a": opt.Some(1), "b": opt.None[int]()}
+
+		data, err := cbor.Marshal(m)
+		require.NoError(t, err)
+
+		var decoded map[string]opt.Opt[int]
+
+		require.NoError(t, cbor.Unmarshal(data, &decoded))
+		require.Equal(t, m, decoded)
+	})
+
+	t.Run("type error", func(t *testing.T) {
+		var decoded opt.Opt[int]
+
+		require.Error(t, cbor.Unmarshal([]byte("aa"), &decoded))
+	})
+}
+
+// binaryID implements only encoding.BinaryMarshaler and keeps its state in an unexported field.
+type binaryID struct {
+	id uint16
+}
+
+func (b binaryID) MarshalBinary() ([]byte, error) {
+	return []byte{byte(b.id >> 8), byte(b.id)}, nil
+}
+
+func (b *binaryID) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return errors.New("invalid id")
+	}
+
+	b.id = uint16(data[0])<<8 | uint16(data[1])
+
+	return nil
+}
+
+func TestTextMarshaler(t *testing.T) {
+	addr := netip.MustParseAddr("1.2.3.4")
+
+	data, err := cbor.Marshal(addr)
+	require.NoError(t, err)
+	require.Equal(t, "67312e322e332e34", hex.EncodeToString(data))
+
+	var decoded netip.Addr
+
+	require.NoError(t, cbor.Unmarshal(data, &decoded))
+	require.Equal(t, addr, decoded)
+
+	type Host struct {
+		Addr opt.Opt[netip.Addr]
+		URL  url.URL
+		ID   binaryID
+	}
+
+	host := Host{
+		Addr: opt.Some(addr),
+		URL:  url.URL{Scheme: "https", Host: "example.com"},
+		ID:   binaryID{id: 0x0102},
+	}
+
+	data, err = cbor.Marshal(host)
+	require.NoError(t, err)
+
+	var decodedHost Host
+
+	require.NoError(t, cbor.Unmarshal(data, &decodedHost))
+	require.Equal(t, host, decodedHost)
+
+	data, err = cbor.Marshal(binaryID{id: 0x0102})
+	require.NoError(t, err)
+	require.Equal(t, "420102", hex.EncodeToString(data))
+}
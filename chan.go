@@ -0,0 +1,76 @@
+package opt
+
+import (
+	"context"
+	"time"
+)
+
+// TryRecv receives a value from the channel without blocking.
+//
+// Returns [None] if no value is ready or the channel is closed.
+func TryRecv[T any](ch <-chan T) Opt[T] {
+	select {
+	case value, ok := <-ch:
+		return FromTuple(value, ok)
+	default:
+		return None[T]()
+	}
+}
+
+// Recv receives a value from the channel, blocking until a value is ready,
+// the channel is closed or the context is done.
+//
+// Returns [None] if the channel is closed and the context error if the context is done,
+// so that closed channel is not confused with cancellation.
+func Recv[T any](ctx context.Context, ch <-chan T) (Opt[T], error) {
+	select {
+	case value, ok := <-ch:
+		return FromTuple(value, ok), nil
+	case <-ctx.Done():
+		return Opt[T]{}, ctx.Err()
+	}
+}
+
+// RecvTimeout works like [Recv], but waits for at most the given duration
+// and returns [context.DeadlineExceeded] after that.
+func RecvTimeout[T any](ch <-chan T, timeout time.Duration) (Opt[T], error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case value, ok := <-ch:
+		return FromTuple(value, ok), nil
+	case <-timer.C:
+		return Opt[T]{}, context.DeadlineExceeded
+	}
+}
+
+// TrySend sends a value to the channel without blocking
+// and reports whether the value was sent.
+//
+// Like a regular send, it panics if the channel is closed.
+func TrySend[T any](ch chan<- T, value T) bool {
+	select {
+	case ch <- value:
+		return true
+	default:
+		return false
+	}
+}
+
+// Drain receives all values which are ready without blocking,
+// until the channel is empty or closed.
+//
+// Returns nil if no values were received.
+func Drain[T any](ch <-chan T) []T {
+	var values []T
+
+	for {
+		value := TryRecv(ch)
+		if value.IsNone() {
+			return values
+		}
+
+		values = append(values, value.value)
+	}
+}
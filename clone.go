@@ -0,0 +1,85 @@
+package opt
+
+import "reflect"
+
+// Cloner is implemented by types which can be deep-copied, see [Opt.Clone].
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Clone returns a copy of the option which does not share memory with the original one.
+// The explicitness of the option is preserved.
+//
+// The value is copied as follows:
+//   - If T implements [Cloner], its Clone method is used
+//   - Slices (including []byte) and maps are copied with their elements cloned by the same rules,
+//     nil ones stay nil
+//   - Other values, including pointers, are copied as is. Use [Opt.CloneFunc] for them
+//
+// Clone methods of nil pointers and interfaces are not called.
+func (o Opt[T]) Clone() Opt[T] {
+	if !o.hasValue {
+		return o
+	}
+
+	// comma-ok assertion handles nil interfaces
+	clone, _ := cloneValue(reflect.ValueOf(&o.value).Elem()).Interface().(T)
+
+	return Some(clone)
+}
+
+// CloneFunc returns a copy of the option with the value copied by the given function.
+// The explicitness of the option is preserved.
+func (o Opt[T]) CloneFunc(clone func(T) T) Opt[T] {
+	if !o.hasValue {
+		return o
+	}
+
+	return Some(clone(o.value))
+}
+
+// cloneValue clones slices and maps recursively, calling Clone methods of the elements if they implement [Cloner].
+func cloneValue(v reflect.Value) reflect.Value {
+	if method := v.MethodByName("Clone"); method.IsValid() &&
+		method.Type().NumIn() == 0 && method.Type().NumOut() == 1 && method.Type().Out(0) == v.Type() {
+		// nil pointers and interfaces are not cloned to avoid panics in Clone methods
+		if (v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface) || !v.IsNil() {
+			return method.Call(nil)[0]
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(clone, v)
+
+			return clone
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+
+		for iter := v.MapRange(); iter.Next(); {
+			clone.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+
+		return clone
+	default:
+		return v
+	}
+}
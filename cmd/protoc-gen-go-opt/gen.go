@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/gofeaturespb"
+)
+
+const optPackage = protogen.GoImportPath("github.com/metafates/opt")
+
+// generateFile generates the <name>_opt.pb.go file with accessors for the given proto file.
+// The file is skipped if there are no fields with presence.
+func generateFile(gen *protogen.Plugin, file *protogen.File) error {
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+"_opt.pb.go", file.GoImportPath)
+
+	g.P("// Code generated by protoc-gen-go-opt. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+
+	var generated bool
+
+	for _, message := range messages(file.Messages) {
+		for _, field := range message.Fields {
+			if !field.Desc.HasPresence() || field.Desc.IsList() || field.Desc.IsMap() {
+				continue
+			}
+
+			if err := checkConflicts(message, field); err != nil {
+				return err
+			}
+
+			generateGetter(g, message, field)
+			generateSetter(g, message, field)
+
+			generated = true
+		}
+	}
+
+	if !generated {
+		g.Skip()
+	}
+
+	return nil
+}
+
+// messages returns the given messages and all their nested ones, except for map entries.
+func messages(list []*protogen.Message) []*protogen.Message {
+	var result []*protogen.Message
+
+	for _, message := range list {
+		if message.Desc.IsMapEntry() {
+			continue
+		}
+
+		result = append(result, message)
+		result = append(result, messages(message.Messages)...)
+	}
+
+	return result
+}
+
+// checkConflicts reports an error if the generated methods would clash with the generated struct fields.
+func checkConflicts(message *protogen.Message, field *protogen.Field) error {
+	getter, setter := field.GoName+"Opt", "Set"+field.GoName+"Opt"
+
+	for _, other := range message.Fields {
+		if other.GoName == getter || other.GoName == setter {
+			return fmt.Errorf("protoc-gen-go-opt: %s: accessors of %s conflict with field %s",
+				message.Desc.FullName(), field.Desc.Name(), other.Desc.Name())
+		}
+	}
+
+	return nil
+}
+
+func generateGetter(g *protogen.GeneratedFile, message *protogen.Message, field *protogen.Field) {
+	typ := goType(g, field)
+	some, none := g.QualifiedGoIdent(optPackage.Ident("Some")), g.QualifiedGoIdent(optPackage.Ident("None"))
+
+	g.P("// ", field.GoName, "Opt returns the ", field.Desc.Name(), " field as an option, which is None if the field is unset.")
+	g.P("func (x *", message.GoIdent, ") ", field.GoName, "Opt() ", optType(g, typ), " {")
+
+	switch {
+	case message.APILevel != gofeaturespb.GoFeatures_API_OPEN:
+		has, _ := field.MethodName("Has")
+		get, _ := field.MethodName("Get")
+
+		g.P("if x.", has, "() {")
+		g.P("return ", some, "(x.", get, "())")
+		g.P("}")
+	case isOneofMember(field) && field.Desc.Message() != nil:
+		// the case may be set with a nil message, which is unset like a regular message field
+		g.P("if v, ok := x.Get", field.Oneof.GoName, "().(*", field.GoIdent, "); ok && v.", field.GoName, " != nil {")
+		g.P("return ", some, "(v.", field.GoName, ")")
+		g.P("}")
+	case isOneofMember(field):
+		g.P("if v, ok := x.Get", field.Oneof.GoName, "().(*", field.GoIdent, "); ok {")
+		g.P("return ", some, "(v.", field.GoName, ")")
+		g.P("}")
+	case field.Desc.Message() != nil || field.Desc.Kind() == protoreflect.BytesKind:
+		g.P("if x != nil && x.", field.GoName, " != nil {")
+		g.P("return ", some, "(x.", field.GoName, ")")
+		g.P("}")
+	default:
+		g.P("if x != nil && x.", field.GoName, " != nil {")
+		g.P("return ", some, "(*x.", field.GoName, ")")
+		g.P("}")
+	}
+
+	g.P()
+	g.P("return ", none, "[", typ, "]()")
+	g.P("}")
+	g.P()
+}
+
+func generateSetter(g *protogen.GeneratedFile, message *protogen.Message, field *protogen.Field) {
+	typ := goType(g, field)
+
+	g.P("// Set", field.GoName, "Opt sets the ", field.Desc.Name(), " field to the value of [", optPackage.Ident("Some"), "],")
+	g.P("// clears it on explicit [", optPackage.Ident("None"), "] and leaves it untouched on implicit one.")
+	g.P("func (x *", message.GoIdent, ") Set", field.GoName, "Opt(o ", optType(g, typ), ") {")
+	g.P("if !o.IsExplicit() {")
+	g.P("return")
+	g.P("}")
+	g.P()
+
+	switch {
+	case message.APILevel != gofeaturespb.GoFeatures_API_OPEN:
+		set, _ := field.MethodName("Set")
+		reset, _ := field.MethodName("Clear")
+
+		g.P("if v, ok := o.TryGet(); ok {")
+		g.P("x.", set, "(v)")
+		g.P("} else {")
+		g.P("x.", reset, "()")
+		g.P("}")
+	case isOneofMember(field):
+		g.P("if v, ok := o.TryGet(); ok {")
+		g.P("x.", field.Oneof.GoName, " = &", field.GoIdent, "{", field.GoName, ": v}")
+		g.P("} else if _, ok := x.", field.Oneof.GoName, ".(*", field.GoIdent, "); ok {")
+		g.P("x.", field.Oneof.GoName, " = nil")
+		g.P("}")
+	case field.Desc.Kind() == protoreflect.BytesKind:
+		// nil slice means unset field, so Some(nil) is stored as an empty one
+		g.P("v, ok := o.TryGet()")
+		g.P("if ok && v == nil {")
+		g.P("v = []byte{}")
+		g.P("}")
+		g.P()
+		g.P("x.", field.GoName, " = v")
+	case field.Desc.Message() != nil:
+		g.P("x.", field.GoName, " = o.GetOrEmpty()")
+	default:
+		g.P("x.", field.GoName, " = o.ToPtr()")
+	}
+
+	g.P("}")
+	g.P()
+}
+
+func isOneofMember(field *protogen.Field) bool {
+	return field.Oneof != nil && !field.Oneof.Desc.IsSynthetic()
+}
+
+func optType(g *protogen.GeneratedFile, typ string) string {
+	return g.QualifiedGoIdent(optPackage.Ident("Opt")) + "[" + typ + "]"
+}
+
+// goType returns the Go type of the singular field value.
+func goType(g *protogen.GeneratedFile, field *protogen.Field) string {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.EnumKind:
+		return g.QualifiedGoIdent(field.Enum.GoIdent)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]byte"
+	default:
+		return "*" + g.QualifiedGoIdent(field.Message.GoIdent)
+	}
+}
@@ -0,0 +1,619 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: opaque/user.proto
+
+package opaquepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_ACTIVE      Status = 1
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_ACTIVE",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_ACTIVE":      1,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_opaque_user_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_opaque_user_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+type User struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Name        string                 `protobuf:"bytes,1,opt,name=name,proto3"`
+	xxx_hidden_Age         int32                  `protobuf:"varint,2,opt,name=age,proto3,oneof"`
+	xxx_hidden_Nickname    *string                `protobuf:"bytes,3,opt,name=nickname,proto3,oneof"`
+	xxx_hidden_Avatar      []byte                 `protobuf:"bytes,4,opt,name=avatar,proto3,oneof"`
+	xxx_hidden_Status      Status                 `protobuf:"varint,5,opt,name=status,proto3,enum=opt.test.opaque.Status,oneof"`
+	xxx_hidden_Address     *User_Address          `protobuf:"bytes,6,opt,name=address,proto3"`
+	xxx_hidden_Contact     isUser_Contact         `protobuf_oneof:"contact"`
+	xxx_hidden_Tags        []string               `protobuf:"bytes,9,rep,name=tags,proto3"`
+	xxx_hidden_Labels      map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_opaque_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_opaque_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.xxx_hidden_Name
+	}
+	return ""
+}
+
+func (x *User) GetAge() int32 {
+	if x != nil {
+		return x.xxx_hidden_Age
+	}
+	return 0
+}
+
+func (x *User) GetNickname() string {
+	if x != nil {
+		if x.xxx_hidden_Nickname != nil {
+			return *x.xxx_hidden_Nickname
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *User) GetAvatar() []byte {
+	if x != nil {
+		return x.xxx_hidden_Avatar
+	}
+	return nil
+}
+
+func (x *User) GetStatus() Status {
+	if x != nil {
+		if protoimpl.X.Present(&(x.XXX_presence[0]), 4) {
+			return x.xxx_hidden_Status
+		}
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *User) GetAddress() *User_Address {
+	if x != nil {
+		return x.xxx_hidden_Address
+	}
+	return nil
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		if x, ok := x.xxx_hidden_Contact.(*user_Phone); ok {
+			return x.Phone
+		}
+	}
+	return ""
+}
+
+func (x *User) GetOffice() *User_Address {
+	if x != nil {
+		if x, ok := x.xxx_hidden_Contact.(*user_Office); ok {
+			return x.Office
+		}
+	}
+	return nil
+}
+
+func (x *User) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *User) GetLabels() map[string]string {
+	if x != nil {
+		return x.xxx_hidden_Labels
+	}
+	return nil
+}
+
+func (x *User) SetName(v string) {
+	x.xxx_hidden_Name = v
+}
+
+func (x *User) SetAge(v int32) {
+	x.xxx_hidden_Age = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 9)
+}
+
+func (x *User) SetNickname(v string) {
+	x.xxx_hidden_Nickname = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 9)
+}
+
+func (x *User) SetAvatar(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Avatar = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 9)
+}
+
+func (x *User) SetStatus(v Status) {
+	x.xxx_hidden_Status = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 9)
+}
+
+func (x *User) SetAddress(v *User_Address) {
+	x.xxx_hidden_Address = v
+}
+
+func (x *User) SetPhone(v string) {
+	x.xxx_hidden_Contact = &user_Phone{v}
+}
+
+func (x *User) SetOffice(v *User_Address) {
+	if v == nil {
+		x.xxx_hidden_Contact = nil
+		return
+	}
+	x.xxx_hidden_Contact = &user_Office{v}
+}
+
+func (x *User) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+func (x *User) SetLabels(v map[string]string) {
+	x.xxx_hidden_Labels = v
+}
+
+func (x *User) HasAge() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *User) HasNickname() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *User) HasAvatar() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *User) HasStatus() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *User) HasAddress() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Address != nil
+}
+
+func (x *User) HasContact() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Contact != nil
+}
+
+func (x *User) HasPhone() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.xxx_hidden_Contact.(*user_Phone)
+	return ok
+}
+
+func (x *User) HasOffice() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.xxx_hidden_Contact.(*user_Office)
+	return ok
+}
+
+func (x *User) ClearAge() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Age = 0
+}
+
+func (x *User) ClearNickname() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Nickname = nil
+}
+
+func (x *User) ClearAvatar() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Avatar = nil
+}
+
+func (x *User) ClearStatus() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Status = Status_STATUS_UNSPECIFIED
+}
+
+func (x *User) ClearAddress() {
+	x.xxx_hidden_Address = nil
+}
+
+func (x *User) ClearContact() {
+	x.xxx_hidden_Contact = nil
+}
+
+func (x *User) ClearPhone() {
+	if _, ok := x.xxx_hidden_Contact.(*user_Phone); ok {
+		x.xxx_hidden_Contact = nil
+	}
+}
+
+func (x *User) ClearOffice() {
+	if _, ok := x.xxx_hidden_Contact.(*user_Office); ok {
+		x.xxx_hidden_Contact = nil
+	}
+}
+
+const User_Contact_not_set_case case_User_Contact = 0
+const User_Phone_case case_User_Contact = 7
+const User_Office_case case_User_Contact = 8
+
+func (x *User) WhichContact() case_User_Contact {
+	if x == nil {
+		return User_Contact_not_set_case
+	}
+	switch x.xxx_hidden_Contact.(type) {
+	case *user_Phone:
+		return User_Phone_case
+	case *user_Office:
+		return User_Office_case
+	default:
+		return User_Contact_not_set_case
+	}
+}
+
+type User_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Name     string
+	Age      *int32
+	Nickname *string
+	Avatar   []byte
+	Status   *Status
+	Address  *User_Address
+	// Fields of oneof xxx_hidden_Contact:
+	Phone  *string
+	Office *User_Address
+	// -- end of xxx_hidden_Contact
+	Tags   []string
+	Labels map[string]string
+}
+
+func (b0 User_builder) Build() *User {
+	m0 := &User{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Name = b.Name
+	if b.Age != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 9)
+		x.xxx_hidden_Age = *b.Age
+	}
+	if b.Nickname != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 9)
+		x.xxx_hidden_Nickname = b.Nickname
+	}
+	if b.Avatar != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 9)
+		x.xxx_hidden_Avatar = b.Avatar
+	}
+	if b.Status != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 9)
+		x.xxx_hidden_Status = *b.Status
+	}
+	x.xxx_hidden_Address = b.Address
+	if b.Phone != nil {
+		x.xxx_hidden_Contact = &user_Phone{*b.Phone}
+	}
+	if b.Office != nil {
+		x.xxx_hidden_Contact = &user_Office{b.Office}
+	}
+	x.xxx_hidden_Tags = b.Tags
+	x.xxx_hidden_Labels = b.Labels
+	return m0
+}
+
+type case_User_Contact protoreflect.FieldNumber
+
+func (x case_User_Contact) String() string {
+	md := file_opaque_user_proto_msgTypes[0].Descriptor()
+	if x == 0 {
+		return "not set"
+	}
+	return protoimpl.X.MessageFieldStringOf(md, protoreflect.FieldNumber(x))
+}
+
+type isUser_Contact interface {
+	isUser_Contact()
+}
+
+type user_Phone struct {
+	Phone string `protobuf:"bytes,7,opt,name=phone,proto3,oneof"`
+}
+
+type user_Office struct {
+	Office *User_Address `protobuf:"bytes,8,opt,name=office,proto3,oneof"`
+}
+
+func (*user_Phone) isUser_Contact() {}
+
+func (*user_Office) isUser_Contact() {}
+
+type User_Address struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_City        string                 `protobuf:"bytes,1,opt,name=city,proto3"`
+	xxx_hidden_Zip         *string                `protobuf:"bytes,2,opt,name=zip,proto3,oneof"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *User_Address) Reset() {
+	*x = User_Address{}
+	mi := &file_opaque_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User_Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User_Address) ProtoMessage() {}
+
+func (x *User_Address) ProtoReflect() protoreflect.Message {
+	mi := &file_opaque_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *User_Address) GetCity() string {
+	if x != nil {
+		return x.xxx_hidden_City
+	}
+	return ""
+}
+
+func (x *User_Address) GetZip() string {
+	if x != nil {
+		if x.xxx_hidden_Zip != nil {
+			return *x.xxx_hidden_Zip
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *User_Address) SetCity(v string) {
+	x.xxx_hidden_City = v
+}
+
+func (x *User_Address) SetZip(v string) {
+	x.xxx_hidden_Zip = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *User_Address) HasZip() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *User_Address) ClearZip() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Zip = nil
+}
+
+type User_Address_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	City string
+	Zip  *string
+}
+
+func (b0 User_Address_builder) Build() *User_Address {
+	m0 := &User_Address{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_City = b.City
+	if b.Zip != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_Zip = b.Zip
+	}
+	return m0
+}
+
+var File_opaque_user_proto protoreflect.FileDescriptor
+
+var file_opaque_user_proto_rawDesc = string([]byte{
+	0x0a, 0x11, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x6f, 0x70, 0x74, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x6f, 0x70,
+	0x61, 0x71, 0x75, 0x65, 0x22, 0xad, 0x04, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x15, 0x0a, 0x03, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01,
+	0x52, 0x03, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x08, 0x6e, 0x69,
+	0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x61, 0x76, 0x61,
+	0x74, 0x61, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x03, 0x52, 0x06, 0x61, 0x76, 0x61,
+	0x74, 0x61, 0x72, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6f, 0x70, 0x74, 0x2e, 0x74, 0x65, 0x73,
+	0x74, 0x2e, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48,
+	0x04, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x6f, 0x70, 0x74, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x12, 0x37, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x69, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x6f, 0x70, 0x74, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x06,
+	0x6f, 0x66, 0x66, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x39, 0x0a, 0x06, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6f, 0x70, 0x74,
+	0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x3c, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x69, 0x74, 0x79, 0x12, 0x15, 0x0a, 0x03, 0x7a, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x03, 0x7a, 0x69, 0x70, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f,
+	0x7a, 0x69, 0x70, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x09,
+	0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x61, 0x67,
+	0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x2a, 0x33, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16,
+	0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x42, 0x49, 0x5a, 0x47, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x66, 0x61, 0x74, 0x65,
+	0x73, 0x2f, 0x6f, 0x70, 0x74, 0x2f, 0x63, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2d, 0x6f, 0x70, 0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62, 0x2f, 0x6f, 0x70, 0x61, 0x71,
+	0x75, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var file_opaque_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_opaque_user_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_opaque_user_proto_goTypes = []any{
+	(Status)(0),          // 0: opt.test.opaque.Status
+	(*User)(nil),         // 1: opt.test.opaque.User
+	(*User_Address)(nil), // 2: opt.test.opaque.User.Address
+	nil,                  // 3: opt.test.opaque.User.LabelsEntry
+}
+var file_opaque_user_proto_depIdxs = []int32{
+	0, // 0: opt.test.opaque.User.status:type_name -> opt.test.opaque.Status
+	2, // 1: opt.test.opaque.User.address:type_name -> opt.test.opaque.User.Address
+	2, // 2: opt.test.opaque.User.office:type_name -> opt.test.opaque.User.Address
+	3, // 3: opt.test.opaque.User.labels:type_name -> opt.test.opaque.User.LabelsEntry
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_opaque_user_proto_init() }
+func file_opaque_user_proto_init() {
+	if File_opaque_user_proto != nil {
+		return
+	}
+	file_opaque_user_proto_msgTypes[0].OneofWrappers = []any{
+		(*user_Phone)(nil),
+		(*user_Office)(nil),
+	}
+	file_opaque_user_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_opaque_user_proto_rawDesc), len(file_opaque_user_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_opaque_user_proto_goTypes,
+		DependencyIndexes: file_opaque_user_proto_depIdxs,
+		EnumInfos:         file_opaque_user_proto_enumTypes,
+		MessageInfos:      file_opaque_user_proto_msgTypes,
+	}.Build()
+	File_opaque_user_proto = out.File
+	file_opaque_user_proto_goTypes = nil
+	file_opaque_user_proto_depIdxs = nil
+}
@@ -0,0 +1,192 @@
+// Code generated by protoc-gen-go-opt. DO NOT EDIT.
+// source: opaque/user.proto
+
+package opaquepb
+
+import (
+	opt "github.com/metafates/opt"
+)
+
+// AgeOpt returns the age field as an option, which is None if the field is unset.
+func (x *User) AgeOpt() opt.Opt[int32] {
+	if x.HasAge() {
+		return opt.Some(x.GetAge())
+	}
+
+	return opt.None[int32]()
+}
+
+// SetAgeOpt sets the age field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetAgeOpt(o opt.Opt[int32]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetAge(v)
+	} else {
+		x.ClearAge()
+	}
+}
+
+// NicknameOpt returns the nickname field as an option, which is None if the field is unset.
+func (x *User) NicknameOpt() opt.Opt[string] {
+	if x.HasNickname() {
+		return opt.Some(x.GetNickname())
+	}
+
+	return opt.None[string]()
+}
+
+// SetNicknameOpt sets the nickname field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetNicknameOpt(o opt.Opt[string]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetNickname(v)
+	} else {
+		x.ClearNickname()
+	}
+}
+
+// AvatarOpt returns the avatar field as an option, which is None if the field is unset.
+func (x *User) AvatarOpt() opt.Opt[[]byte] {
+	if x.HasAvatar() {
+		return opt.Some(x.GetAvatar())
+	}
+
+	return opt.None[[]byte]()
+}
+
+// SetAvatarOpt sets the avatar field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetAvatarOpt(o opt.Opt[[]byte]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetAvatar(v)
+	} else {
+		x.ClearAvatar()
+	}
+}
+
+// StatusOpt returns the status field as an option, which is None if the field is unset.
+func (x *User) StatusOpt() opt.Opt[Status] {
+	if x.HasStatus() {
+		return opt.Some(x.GetStatus())
+	}
+
+	return opt.None[Status]()
+}
+
+// SetStatusOpt sets the status field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetStatusOpt(o opt.Opt[Status]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetStatus(v)
+	} else {
+		x.ClearStatus()
+	}
+}
+
+// AddressOpt returns the address field as an option, which is None if the field is unset.
+func (x *User) AddressOpt() opt.Opt[*User_Address] {
+	if x.HasAddress() {
+		return opt.Some(x.GetAddress())
+	}
+
+	return opt.None[*User_Address]()
+}
+
+// SetAddressOpt sets the address field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetAddressOpt(o opt.Opt[*User_Address]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetAddress(v)
+	} else {
+		x.ClearAddress()
+	}
+}
+
+// PhoneOpt returns the phone field as an option, which is None if the field is unset.
+func (x *User) PhoneOpt() opt.Opt[string] {
+	if x.HasPhone() {
+		return opt.Some(x.GetPhone())
+	}
+
+	return opt.None[string]()
+}
+
+// SetPhoneOpt sets the phone field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetPhoneOpt(o opt.Opt[string]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetPhone(v)
+	} else {
+		x.ClearPhone()
+	}
+}
+
+// OfficeOpt returns the office field as an option, which is None if the field is unset.
+func (x *User) OfficeOpt() opt.Opt[*User_Address] {
+	if x.HasOffice() {
+		return opt.Some(x.GetOffice())
+	}
+
+	return opt.None[*User_Address]()
+}
+
+// SetOfficeOpt sets the office field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetOfficeOpt(o opt.Opt[*User_Address]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetOffice(v)
+	} else {
+		x.ClearOffice()
+	}
+}
+
+// ZipOpt returns the zip field as an option, which is None if the field is unset.
+func (x *User_Address) ZipOpt() opt.Opt[string] {
+	if x.HasZip() {
+		return opt.Some(x.GetZip())
+	}
+
+	return opt.None[string]()
+}
+
+// SetZipOpt sets the zip field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User_Address) SetZipOpt(o opt.Opt[string]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.SetZip(v)
+	} else {
+		x.ClearZip()
+	}
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-opt. DO NOT EDIT.
+// source: open/user.proto
+
+package openpb
+
+import (
+	opt "github.com/metafates/opt"
+)
+
+// AgeOpt returns the age field as an option, which is None if the field is unset.
+func (x *User) AgeOpt() opt.Opt[int32] {
+	if x != nil && x.Age != nil {
+		return opt.Some(*x.Age)
+	}
+
+	return opt.None[int32]()
+}
+
+// SetAgeOpt sets the age field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetAgeOpt(o opt.Opt[int32]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	x.Age = o.ToPtr()
+}
+
+// NicknameOpt returns the nickname field as an option, which is None if the field is unset.
+func (x *User) NicknameOpt() opt.Opt[string] {
+	if x != nil && x.Nickname != nil {
+		return opt.Some(*x.Nickname)
+	}
+
+	return opt.None[string]()
+}
+
+// SetNicknameOpt sets the nickname field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetNicknameOpt(o opt.Opt[string]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	x.Nickname = o.ToPtr()
+}
+
+// AvatarOpt returns the avatar field as an option, which is None if the field is unset.
+func (x *User) AvatarOpt() opt.Opt[[]byte] {
+	if x != nil && x.Avatar != nil {
+		return opt.Some(x.Avatar)
+	}
+
+	return opt.None[[]byte]()
+}
+
+// SetAvatarOpt sets the avatar field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetAvatarOpt(o opt.Opt[[]byte]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	v, ok := o.TryGet()
+	if ok && v == nil {
+		v = []byte{}
+	}
+
+	x.Avatar = v
+}
+
+// StatusOpt returns the status field as an option, which is None if the field is unset.
+func (x *User) StatusOpt() opt.Opt[Status] {
+	if x != nil && x.Status != nil {
+		return opt.Some(*x.Status)
+	}
+
+	return opt.None[Status]()
+}
+
+// SetStatusOpt sets the status field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetStatusOpt(o opt.Opt[Status]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	x.Status = o.ToPtr()
+}
+
+// AddressOpt returns the address field as an option, which is None if the field is unset.
+func (x *User) AddressOpt() opt.Opt[*User_Address] {
+	if x != nil && x.Address != nil {
+		return opt.Some(x.Address)
+	}
+
+	return opt.None[*User_Address]()
+}
+
+// SetAddressOpt sets the address field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetAddressOpt(o opt.Opt[*User_Address]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	x.Address = o.GetOrEmpty()
+}
+
+// PhoneOpt returns the phone field as an option, which is None if the field is unset.
+func (x *User) PhoneOpt() opt.Opt[string] {
+	if v, ok := x.GetContact().(*User_Phone); ok {
+		return opt.Some(v.Phone)
+	}
+
+	return opt.None[string]()
+}
+
+// SetPhoneOpt sets the phone field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetPhoneOpt(o opt.Opt[string]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.Contact = &User_Phone{Phone: v}
+	} else if _, ok := x.Contact.(*User_Phone); ok {
+		x.Contact = nil
+	}
+}
+
+// OfficeOpt returns the office field as an option, which is None if the field is unset.
+func (x *User) OfficeOpt() opt.Opt[*User_Address] {
+	if v, ok := x.GetContact().(*User_Office); ok && v.Office != nil {
+		return opt.Some(v.Office)
+	}
+
+	return opt.None[*User_Address]()
+}
+
+// SetOfficeOpt sets the office field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User) SetOfficeOpt(o opt.Opt[*User_Address]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	if v, ok := o.TryGet(); ok {
+		x.Contact = &User_Office{Office: v}
+	} else if _, ok := x.Contact.(*User_Office); ok {
+		x.Contact = nil
+	}
+}
+
+// ZipOpt returns the zip field as an option, which is None if the field is unset.
+func (x *User_Address) ZipOpt() opt.Opt[string] {
+	if x != nil && x.Zip != nil {
+		return opt.Some(*x.Zip)
+	}
+
+	return opt.None[string]()
+}
+
+// SetZipOpt sets the zip field to the value of [opt.Some],
+// clears it on explicit [opt.None] and leaves it untouched on implicit one.
+func (x *User_Address) SetZipOpt(o opt.Opt[string]) {
+	if !o.IsExplicit() {
+		return
+	}
+
+	x.Zip = o.ToPtr()
+}
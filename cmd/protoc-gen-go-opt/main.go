@@ -0,0 +1,67 @@
+// The protoc-gen-go-opt binary is a protoc plugin generating [opt.Opt] accessors
+// for the messages generated by protoc-gen-go.
+//
+// For every field with presence, such as proto3 optional and proto2 scalars, oneof members and message fields,
+// it emits a pair of methods into the <name>_opt.pb.go file next to the <name>.pb.go:
+//
+//	func (x *User) AgeOpt() opt.Opt[int32]
+//	func (x *User) SetAgeOpt(o opt.Opt[int32])
+//
+// The getter returns [opt.None] for unset fields instead of the zero value.
+// The setter sets the field on [opt.Some], clears it on explicit [opt.None] and leaves it untouched on implicit one,
+// so a patch with option fields can be applied as is.
+//
+// Usage:
+//
+//	protoc --go_out=. --go-opt_out=. user.proto
+//
+// The plugin accepts the same parameters as protoc-gen-go, e.g. paths=source_relative and M mappings,
+// and must be run with the same ones to place the accessors into the right package.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	if len(os.Args) == 2 && os.Args[1] == "--version" {
+		fmt.Fprintf(os.Stdout, "%v %v\n", filepath.Base(os.Args[0]), version())
+		os.Exit(0)
+	}
+
+	protogen.Options{}.Run(run)
+}
+
+func run(gen *protogen.Plugin) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL |
+		pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+	gen.SupportedEditionsMinimum = descriptorpb.Edition_EDITION_PROTO2
+	gen.SupportedEditionsMaximum = descriptorpb.Edition_EDITION_2023
+
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+
+		if err := generateFile(gen, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func version() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+
+	return "(unknown)"
+}
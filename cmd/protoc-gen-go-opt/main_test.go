@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/metafates/opt"
+	"github.com/metafates/opt/cmd/protoc-gen-go-opt/internal/testpb/opaquepb"
+	"github.com/metafates/opt/cmd/protoc-gen-go-opt/internal/testpb/openpb"
+)
+
+// testdata/user.binpb is a descriptor set of testdata/open/user.proto and testdata/opaque/user.proto
+// and the generated test packages are checked in, so no protoc is needed to run the tests.
+//
+// Run the tests with -update flag to regenerate the _opt.pb.go files after changing the generator.
+// The user.pb.go files are generated by protoc-gen-go separately, after changing the protos:
+//
+//	protoc -I testdata --include_imports -o testdata/user.binpb open/user.proto opaque/user.proto
+//	protoc -I testdata --go_out=. --go_opt=module=github.com/metafates/opt/cmd/protoc-gen-go-opt \
+//		--go_opt=apilevelMopaque/user.proto=API_OPAQUE open/user.proto opaque/user.proto
+var update = flag.Bool("update", false, "regenerate the _opt.pb.go files")
+
+const module = "github.com/metafates/opt/cmd/protoc-gen-go-opt"
+
+func newPlugin(t *testing.T) *protogen.Plugin {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "user.binpb"))
+	require.NoError(t, err)
+
+	var set descriptorpb.FileDescriptorSet
+
+	require.NoError(t, proto.Unmarshal(data, &set))
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"open/user.proto", "opaque/user.proto"},
+		Parameter:      proto.String("module=" + module + ",apilevelMopaque/user.proto=API_OPAQUE"),
+		ProtoFile:      set.File,
+	}
+
+	gen, err := protogen.Options{}.New(req)
+	require.NoError(t, err)
+
+	return gen
+}
+
+func TestGenerate(t *testing.T) {
+	gen := newPlugin(t)
+
+	require.NoError(t, run(gen))
+
+	resp := gen.Response()
+	require.Empty(t, resp.GetError())
+	require.NotEmpty(t, resp.File)
+
+	for _, f := range resp.File {
+		if *update {
+			require.NoError(t, os.WriteFile(f.GetName(), []byte(f.GetContent()), 0o644))
+
+			continue
+		}
+
+		want, err := os.ReadFile(f.GetName())
+		require.NoError(t, err, "run the tests with -update flag to generate the file")
+		require.Equal(t, string(want), f.GetContent(), "%s is outdated, run the tests with -update flag", f.GetName())
+	}
+}
+
+func TestGenerate_Conflict(t *testing.T) {
+	gen := newPlugin(t)
+
+	for _, f := range gen.Files {
+		for _, message := range f.Messages {
+			message.Fields[0].GoName = "AgeOpt"
+		}
+	}
+
+	require.ErrorContains(t, run(gen), "conflict")
+}
+
+func TestAccessors_Open(t *testing.T) {
+	var user *openpb.User
+
+	require.Equal(t, opt.None[int32](), user.AgeOpt(), "nil message")
+
+	user = &openpb.User{}
+
+	require.Equal(t, opt.None[int32](), user.AgeOpt())
+	require.Equal(t, opt.None[*openpb.User_Address](), user.AddressOpt())
+	require.Equal(t, opt.None[string](), user.PhoneOpt())
+
+	user.SetAgeOpt(opt.Some[int32](0))
+	user.SetNicknameOpt(opt.Some("bob"))
+	user.SetAvatarOpt(opt.Some[[]byte](nil))
+	user.SetStatusOpt(opt.Some(openpb.Status_STATUS_ACTIVE))
+	user.SetAddressOpt(opt.Some(&openpb.User_Address{City: "Paris"}))
+	user.SetPhoneOpt(opt.Some("123"))
+
+	require.Equal(t, opt.Some[int32](0), user.AgeOpt())
+	require.Equal(t, opt.Some("bob"), user.NicknameOpt())
+	require.Equal(t, opt.Some([]byte{}), user.AvatarOpt())
+	require.Equal(t, opt.Some(openpb.Status_STATUS_ACTIVE), user.StatusOpt())
+	require.Equal(t, "Paris", user.AddressOpt().MustGet().GetCity())
+	require.Equal(t, opt.None[string](), user.AddressOpt().MustGet().ZipOpt())
+	require.Equal(t, opt.Some("123"), user.PhoneOpt())
+	require.Equal(t, opt.None[*openpb.User_Address](), user.OfficeOpt())
+
+	// implicit None leaves the fields untouched
+	var patch struct {
+		Age   opt.Opt[int32]
+		Phone opt.Opt[string]
+	}
+
+	user.SetAgeOpt(patch.Age)
+	user.SetPhoneOpt(patch.Phone)
+
+	require.Equal(t, opt.Some[int32](0), user.AgeOpt())
+	require.Equal(t, opt.Some("123"), user.PhoneOpt())
+
+	// explicit None clears the fields, but not other oneof members
+	user.SetOfficeOpt(opt.None[*openpb.User_Address]())
+	require.Equal(t, opt.Some("123"), user.PhoneOpt())
+
+	// oneof case with a nil message is unset
+	contact := user.Contact
+	user.Contact = &openpb.User_Office{}
+	require.Equal(t, opt.None[*openpb.User_Address](), user.OfficeOpt())
+	user.Contact = contact
+
+	user.SetAgeOpt(opt.None[int32]())
+	user.SetAvatarOpt(opt.None[[]byte]())
+	user.SetAddressOpt(opt.None[*openpb.User_Address]())
+	user.SetPhoneOpt(opt.None[string]())
+
+	require.Nil(t, user.Age)
+	require.Nil(t, user.Avatar)
+	require.Nil(t, user.Address)
+	require.Nil(t, user.Contact)
+}
+
+func TestAccessors_Opaque(t *testing.T) {
+	var user *opaquepb.User
+
+	require.Equal(t, opt.None[int32](), user.AgeOpt(), "nil message")
+
+	user = &opaquepb.User{}
+
+	require.Equal(t, opt.None[int32](), user.AgeOpt())
+	require.Equal(t, opt.None[string](), user.PhoneOpt())
+
+	user.SetAgeOpt(opt.Some[int32](0))
+	user.SetAddressOpt(opt.Some(opaquepb.User_Address_builder{City: "Paris"}.Build()))
+	user.SetPhoneOpt(opt.Some("123"))
+
+	require.Equal(t, opt.Some[int32](0), user.AgeOpt())
+	require.Equal(t, "Paris", user.AddressOpt().MustGet().GetCity())
+	require.Equal(t, opt.Some("123"), user.PhoneOpt())
+
+	user.SetAgeOpt(opt.Opt[int32]{})
+	require.True(t, user.HasAge())
+
+	user.SetAgeOpt(opt.None[int32]())
+	user.SetPhoneOpt(opt.None[string]())
+
+	require.False(t, user.HasAge())
+	require.False(t, user.HasPhone())
+}
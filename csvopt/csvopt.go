@@ -0,0 +1,268 @@
+// Package csvopt maps CSV rows to and from structs with [opt.Opt] fields by header name.
+//
+// Struct fields are mapped to columns with `csv:"column"` tags, defaulting to the field name.
+// Fields tagged with `csv:"-"` are ignored and embedded structs are flattened.
+//
+// Decoding distinguishes missing columns from empty cells:
+//   - Missing column leaves the option implicit None
+//   - Empty cell or [Decoder.NullToken] is decoded as explicit None
+//   - Otherwise, cell is parsed into Some
+//
+// Cells are parsed in the same way as by [opt.Parse], including parsers registered with [opt.RegisterParser].
+package csvopt
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/textconv"
+)
+
+// Error is an error of decoding or encoding a single cell.
+type Error struct {
+	// Line is 1-based line number of the cell, zero when encoding
+	Line int
+
+	// Column is 1-based column number of the cell in bytes, zero when encoding
+	Column int
+
+	// Header is the column header of the cell
+	Header string
+
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("csvopt: column %q: %v", e.Header, e.Err)
+	}
+
+	return fmt.Sprintf("csvopt: line %d, column %d (%q): %v", e.Line, e.Column, e.Header, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads structs of type T from the CSV reader row by row.
+// The first row is treated as a header.
+type Decoder[T any] struct {
+	// NullToken is an additional cell value which is decoded as explicit None, e.g. "NULL".
+	// Empty cells are always decoded as explicit None.
+	NullToken string
+
+	// DisallowUnknownColumns reports header columns not mapped to any field as errors.
+	DisallowUnknownColumns bool
+
+	r       *csv.Reader
+	columns []*field // columns[i] is the field of i-th cell or nil
+}
+
+// NewDecoder returns a new decoder reading from r.
+func NewDecoder[T any](r *csv.Reader) *Decoder[T] {
+	return &Decoder[T]{r: r}
+}
+
+// Decode reads the next row into v.
+// Returns [io.EOF] when there are no more rows.
+func (d *Decoder[T]) Decode(v *T) error {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return err
+		}
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(v).Elem()
+	value.SetZero()
+
+	for i, cell := range record {
+		if i >= len(d.columns) || d.columns[i] == nil {
+			continue
+		}
+
+		f := d.columns[i]
+
+		if err := d.decodeCell(value.FieldByIndex(f.index), cell); err != nil {
+			line, column := d.r.FieldPos(i)
+
+			return &Error{Line: line, Column: column, Header: f.header, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder[T]) readHeader() error {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("csvopt: expected struct, got %s", t)
+	}
+
+	header, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+
+	byHeader := make(map[string]*field)
+
+	for _, f := range fields(t) {
+		byHeader[f.header] = &f
+	}
+
+	d.columns = make([]*field, len(header))
+
+	for i, name := range header {
+		f, ok := byHeader[name]
+		if !ok && d.DisallowUnknownColumns {
+			line, column := d.r.FieldPos(i)
+
+			return &Error{Line: line, Column: column, Header: name, Err: errors.New("unknown column")}
+		}
+
+		d.columns[i] = f
+	}
+
+	return nil
+}
+
+func (d *Decoder[T]) decodeCell(dst reflect.Value, cell string) error {
+	if !optreflect.Is(dst.Type()) {
+		return textconv.Parse(dst, cell)
+	}
+
+	if cell == "" || d.NullToken != "" && cell == d.NullToken {
+		optreflect.SetNone(dst)
+
+		return nil
+	}
+
+	value := reflect.New(optreflect.Elem(dst.Type())).Elem()
+
+	if err := textconv.Parse(value, cell); err != nil {
+		return err
+	}
+
+	optreflect.SetSome(dst, value)
+
+	return nil
+}
+
+// Encoder writes structs of type T to the CSV writer row by row.
+// The header is written before the first row.
+//
+// Call [csv.Writer.Flush] on the underlying writer after encoding.
+type Encoder[T any] struct {
+	// NullToken is written for explicit None values. Implicit None is always written as an empty cell.
+	NullToken string
+
+	w      *csv.Writer
+	fields []field
+}
+
+// NewEncoder returns a new encoder writing to w.
+func NewEncoder[T any](w *csv.Writer) *Encoder[T] {
+	return &Encoder[T]{w: w}
+}
+
+// Encode writes v as the next row.
+func (e *Encoder[T]) Encode(v T) error {
+	if e.fields == nil {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	value := reflect.ValueOf(v)
+	record := make([]string, len(e.fields))
+
+	for i, f := range e.fields {
+		cell, err := e.encodeCell(value.FieldByIndex(f.index))
+		if err != nil {
+			return &Error{Header: f.header, Err: err}
+		}
+
+		record[i] = cell
+	}
+
+	return e.w.Write(record)
+}
+
+func (e *Encoder[T]) writeHeader() error {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("csvopt: expected struct, got %s", t)
+	}
+
+	e.fields = fields(t)
+
+	header := make([]string, len(e.fields))
+
+	for i, f := range e.fields {
+		header[i] = f.header
+	}
+
+	return e.w.Write(header)
+}
+
+func (e *Encoder[T]) encodeCell(value reflect.Value) (string, error) {
+	if !optreflect.Is(value.Type()) {
+		return textconv.Format(value)
+	}
+
+	if !optreflect.IsExplicit(value) {
+		return "", nil
+	}
+
+	inner, ok := optreflect.Get(value)
+	if !ok {
+		return e.NullToken, nil
+	}
+
+	return textconv.Format(inner)
+}
+
+type field struct {
+	header string
+	index  []int
+}
+
+func fields(t reflect.Type) []field {
+	var result []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		header, tagged := f.Tag.Lookup("csv")
+		header, _, _ = strings.Cut(header, ",")
+
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct && !optreflect.Is(f.Type) {
+			for _, embedded := range fields(f.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				result = append(result, embedded)
+			}
+
+			continue
+		}
+
+		if header == "-" || !f.IsExported() {
+			continue
+		}
+
+		if header == "" {
+			header = f.Name
+		}
+
+		result = append(result, field{header: header, index: []int{i}})
+	}
+
+	return result
+}
@@ -0,0 +1,135 @@
+package csvopt
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+type Record struct {
+	ID      int                `csv:"id"`
+	Name    opt.Opt[string]    `csv:"name"`
+	Score   opt.Opt[float64]   `csv:"score"`
+	Joined  opt.Opt[time.Time] `csv:"joined"`
+	Comment opt.Opt[string]    `csv:"comment"`
+	Ignored string             `csv:"-"`
+}
+
+func decodeAll(t *testing.T, d *Decoder[Record]) []Record {
+	t.Helper()
+
+	var records []Record
+
+	for {
+		var r Record
+
+		err := d.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+
+		require.NoError(t, err)
+
+		records = append(records, r)
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	input := "id,name,score,joined,extra\n" +
+		"1,alice,9.5,2024-01-02T00:00:00Z,x\n" +
+		"2,,NULL,,y\n"
+
+	d := NewDecoder[Record](csv.NewReader(strings.NewReader(input)))
+	d.NullToken = "NULL"
+
+	records := decodeAll(t, d)
+
+	require.Equal(t, []Record{
+		{
+			ID:     1,
+			Name:   opt.Some("alice"),
+			Score:  opt.Some(9.5),
+			Joined: opt.Some(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			ID:     2,
+			Name:   opt.None[string](),
+			Score:  opt.None[float64](),
+			Joined: opt.None[time.Time](),
+		},
+	}, records)
+
+	require.False(t, records[0].Comment.IsExplicit())
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		input := "id,score\n1,2\n2,high\n"
+
+		d := NewDecoder[Record](csv.NewReader(strings.NewReader(input)))
+
+		var r Record
+
+		require.NoError(t, d.Decode(&r))
+
+		err := d.Decode(&r)
+
+		var csvErr *Error
+		require.ErrorAs(t, err, &csvErr)
+		require.Equal(t, 3, csvErr.Line)
+		require.Equal(t, 3, csvErr.Column)
+		require.Equal(t, "score", csvErr.Header)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		d := NewDecoder[Record](csv.NewReader(strings.NewReader("id,extra\n1,2\n")))
+		d.DisallowUnknownColumns = true
+
+		var r Record
+
+		var csvErr *Error
+		require.ErrorAs(t, d.Decode(&r), &csvErr)
+		require.Equal(t, "extra", csvErr.Header)
+	})
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	var buf strings.Builder
+
+	w := csv.NewWriter(&buf)
+
+	e := NewEncoder[Record](w)
+	e.NullToken = "NULL"
+
+	records := []Record{
+		{ID: 1, Name: opt.Some("alice"), Score: opt.Some(9.5), Joined: opt.Some(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))},
+		{ID: 2, Name: opt.None[string]()},
+	}
+
+	for _, r := range records {
+		require.NoError(t, e.Encode(r))
+	}
+
+	w.Flush()
+	require.NoError(t, w.Error())
+
+	require.Equal(t, "id,name,score,joined,comment\n"+
+		"1,alice,9.5,2024-01-02T00:00:00Z,\n"+
+		"2,NULL,,,\n", buf.String())
+
+	d := NewDecoder[Record](csv.NewReader(strings.NewReader(buf.String())))
+	d.NullToken = "NULL"
+
+	decoded := decodeAll(t, d)
+
+	require.Equal(t, records[0].Name, decoded[0].Name)
+	require.Equal(t, opt.None[string](), decoded[1].Name)
+}
@@ -0,0 +1,184 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/textconv"
+)
+
+// Defaulter is implemented by structs with computed defaults, see [ApplyDefaults].
+type Defaulter interface {
+	// SetDefaults sets the defaults which can not be expressed with tags, e.g. based on other fields.
+	// It is called after the tag defaults of the struct and its nested structs are applied.
+	SetDefaults()
+}
+
+var (
+	defaulterType = reflect.TypeFor[Defaulter]()
+	timeType      = reflect.TypeFor[time.Time]()
+)
+
+// ApplyDefaults fills the fields of the struct pointed to by ptr with the values of their `default:"..."` tags.
+//
+// Defaults are applied only to implicit options, so explicitly set values, including explicit [None], are kept:
+//
+//	type Config struct {
+//		Port opt.Opt[int] `default:"8080"`
+//	}
+//
+// Fields which are not options are set only if they have zero values.
+//
+// Values are parsed in the same way as by [Parse], including parsers registered with [RegisterParser].
+// Slices are parsed from comma-separated values, e.g. `default:"a,b"`.
+//
+// Nested structs, non-nil pointers to them, slices of them and options of them are walked recursively,
+// and [Defaulter.SetDefaults] is called for every struct implementing it.
+func ApplyDefaults(ptr any) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("opt: ApplyDefaults: expected non-nil pointer to struct, got %T", ptr)
+	}
+
+	return applyDefaults(rv.Elem())
+}
+
+func applyDefaults(v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		field := v.Field(i)
+
+		if tag, ok := f.Tag.Lookup("default"); ok {
+			if err := applyDefault(field, tag); err != nil {
+				return fmt.Errorf("opt: ApplyDefaults: field %s: default %q: %w", f.Name, tag, err)
+			}
+		}
+
+		if err := applyNested(field); err != nil {
+			return err
+		}
+	}
+
+	if v.Addr().Type().Implements(defaulterType) {
+		v.Addr().Interface().(Defaulter).SetDefaults()
+	}
+
+	return nil
+}
+
+func applyDefault(field reflect.Value, tag string) error {
+	if !optreflect.Is(field.Type()) {
+		if !field.IsZero() {
+			return nil
+		}
+
+		return parseDefault(field, tag)
+	}
+
+	if optreflect.IsExplicit(field) {
+		return nil
+	}
+
+	value := reflect.New(optreflect.Elem(field.Type())).Elem()
+
+	if err := parseDefault(value, tag); err != nil {
+		return err
+	}
+
+	optreflect.SetSome(field, value)
+
+	return nil
+}
+
+func parseDefault(dst reflect.Value, s string) error {
+	if dst.Kind() == reflect.Slice && !textconv.CanParse(dst.Type()) {
+		parts := strings.Split(s, ",")
+		slice := reflect.MakeSlice(dst.Type(), len(parts), len(parts))
+
+		for i, part := range parts {
+			if err := parseDefault(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+
+		dst.Set(slice)
+
+		return nil
+	}
+
+	if !textconv.CanParse(dst.Type()) {
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		dst.Set(reflect.New(dst.Type().Elem()))
+		dst = dst.Elem()
+	}
+
+	return textconv.Parse(dst, s)
+}
+
+func applyNested(v reflect.Value) error {
+	if optreflect.Is(v.Type()) {
+		inner, ok := optreflect.Get(v)
+		if !ok || !hasNestedStructs(inner.Type()) {
+			return nil
+		}
+
+		// the value is copied, as the contained value is not addressable
+		value := reflect.New(inner.Type()).Elem()
+		value.Set(inner)
+
+		if err := applyNested(value); err != nil {
+			return err
+		}
+
+		optreflect.SetSome(v, value)
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+
+		return applyNested(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return nil
+		}
+
+		return applyDefaults(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := applyNested(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasNestedStructs reports whether values of the type may contain structs to apply defaults to.
+func hasNestedStructs(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return hasNestedStructs(t.Elem())
+	case reflect.Struct:
+		return t != timeType
+	default:
+		return false
+	}
+}
@@ -0,0 +1,304 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var _ interface {
+	json.Marshaler
+	json.Unmarshaler
+} = (*Either[any, any])(nil)
+
+// Either represents a value of one of two types: either [Left] or [Right].
+//
+// By convention, [Left] is used for the failure or alternative payload and [Right] for the main one.
+// The zero value is [Left] with the zero value of L.
+type Either[L, R any] struct {
+	left    L
+	right   R
+	isRight bool
+}
+
+// Left returns either with the left value.
+func Left[L, R any](value L) Either[L, R] {
+	return Either[L, R]{left: value}
+}
+
+// Right returns either with the right value.
+func Right[L, R any](value R) Either[L, R] {
+	return Either[L, R]{right: value, isRight: true}
+}
+
+// IsLeft returns true if the either is a [Left] value.
+func (e Either[L, R]) IsLeft() bool {
+	return !e.isRight
+}
+
+// IsRight returns true if the either is a [Right] value.
+func (e Either[L, R]) IsRight() bool {
+	return e.isRight
+}
+
+// LeftOpt returns [Some] with the left value if the either is [Left] or [None] otherwise.
+func (e Either[L, R]) LeftOpt() Opt[L] {
+	return FromTuple(e.left, !e.isRight)
+}
+
+// RightOpt returns [Some] with the right value if the either is [Right] or [None] otherwise.
+func (e Either[L, R]) RightOpt() Opt[R] {
+	return FromTuple(e.right, e.isRight)
+}
+
+// Swap returns [Right] if the either is [Left] and vice versa.
+func (e Either[L, R]) Swap() Either[R, L] {
+	if e.isRight {
+		return Left[R, L](e.right)
+	}
+
+	return Right[R](e.left)
+}
+
+func (e Either[L, R]) String() string {
+	if e.isRight {
+		return fmt.Sprintf("Right(%v)", e.right)
+	}
+
+	return fmt.Sprintf("Left(%v)", e.left)
+}
+
+// MapLeft maps the left value by applying a function to it (if [Left]) or returns the right value (if [Right]).
+func MapLeft[L, R, U any](either Either[L, R], f func(L) U) Either[U, R] {
+	if either.isRight {
+		return Right[U](either.right)
+	}
+
+	return Left[U, R](f(either.left))
+}
+
+// MapRight maps the right value by applying a function to it (if [Right]) or returns the left value (if [Left]).
+func MapRight[L, R, U any](either Either[L, R], f func(R) U) Either[L, U] {
+	if either.isRight {
+		return Right[L](f(either.right))
+	}
+
+	return Left[L, U](either.left)
+}
+
+// Fold returns the result of calling `left` with the left value (if [Left])
+// or `right` with the right value (if [Right]).
+func Fold[L, R, T any](either Either[L, R], left func(L) T, right func(R) T) T {
+	if either.isRight {
+		return right(either.right)
+	}
+
+	return left(either.left)
+}
+
+// EitherTag configures the tagged union JSON encoding of [Either],
+// see [MarshalEither] and [UnmarshalEither].
+//
+// The zero tag is the one used by [Either.MarshalJSON], e.g. {"type":"right","value":42}.
+// Empty Left, Right and Value of other tags default to "left", "right" and "value".
+type EitherTag struct {
+	// Key is the discriminator key, required unless the whole tag is zero
+	Key string
+
+	// Left and Right are the discriminator values of the branches, must be different
+	Left, Right string
+
+	// Value is the key of the payload
+	Value string
+
+	// Inline inlines the discriminator into the payload, which must be a JSON object.
+	// Value is ignored in this case.
+	Inline bool
+}
+
+var defaultEitherTag = EitherTag{Key: "type", Left: "left", Right: "right", Value: "value"}
+
+// withDefaults fills empty fields of the tag with the default ones and validates it.
+func (t EitherTag) withDefaults() (EitherTag, error) {
+	if t == (EitherTag{}) {
+		return defaultEitherTag, nil
+	}
+
+	if t.Key == "" {
+		return t, errors.New("opt: either tag key is empty")
+	}
+
+	if t.Left == "" {
+		t.Left = defaultEitherTag.Left
+	}
+
+	if t.Right == "" {
+		t.Right = defaultEitherTag.Right
+	}
+
+	if t.Value == "" {
+		t.Value = defaultEitherTag.Value
+	}
+
+	if t.Left == t.Right {
+		return t, fmt.Errorf("opt: either tag has the same left and right discriminator %q", t.Left)
+	}
+
+	if !t.Inline && t.Key == t.Value {
+		return t, fmt.Errorf("opt: either tag has the same discriminator and value key %q", t.Key)
+	}
+
+	return t, nil
+}
+
+// MarshalJSON implemenets [json.Marshaler] interface as a tagged union, e.g. {"type":"right","value":42}.
+// Use [MarshalEither] for other tags.
+func (e Either[L, R]) MarshalJSON() ([]byte, error) {
+	return MarshalEither(e, defaultEitherTag)
+}
+
+// UnmarshalJSON implemenets [json.Unmarshaler] interface, see [Either.MarshalJSON].
+// Use [UnmarshalEither] for other tags.
+func (e *Either[L, R]) UnmarshalJSON(data []byte) error {
+	either, err := UnmarshalEither[L, R](data, defaultEitherTag)
+	if err != nil {
+		return err
+	}
+
+	*e = either
+
+	return nil
+}
+
+// MarshalEither encodes the either as a JSON tagged union using the given tag.
+// The zero tag is the one used by [Either.MarshalJSON].
+func MarshalEither[L, R any](either Either[L, R], tag EitherTag) ([]byte, error) {
+	tag, err := tag.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		payload       []byte
+		discriminator string
+	)
+
+	if either.isRight {
+		discriminator = tag.Right
+		payload, err = json.Marshal(either.right)
+	} else {
+		discriminator = tag.Left
+		payload, err = json.Marshal(either.left)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !tag.Inline {
+		return json.Marshal(map[string]any{
+			tag.Key:   discriminator,
+			tag.Value: json.RawMessage(payload),
+		})
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("opt: inlined either payload must be a JSON object, got %s", payload)
+	}
+
+	if _, ok := fields[tag.Key]; ok {
+		return nil, fmt.Errorf("opt: inlined either payload already contains discriminator key %q", tag.Key)
+	}
+
+	key, err := json.Marshal(tag.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(discriminator)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(value)
+
+	if len(fields) > 0 {
+		buf.WriteByte(',')
+		buf.Write(bytes.TrimSpace(payload)[1:])
+	} else {
+		buf.WriteByte('}')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalEither decodes the JSON tagged union encoded with the given tag, see [MarshalEither].
+func UnmarshalEither[L, R any](data []byte, tag EitherTag) (Either[L, R], error) {
+	tag, err := tag.withDefaults()
+	if err != nil {
+		return Either[L, R]{}, err
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Either[L, R]{}, err
+	}
+
+	if fields == nil {
+		return Either[L, R]{}, errors.New("opt: either must be a JSON object, got null")
+	}
+
+	rawDiscriminator, ok := fields[tag.Key]
+	if !ok {
+		return Either[L, R]{}, fmt.Errorf("opt: either discriminator key %q is missing", tag.Key)
+	}
+
+	var discriminator string
+
+	if err := json.Unmarshal(rawDiscriminator, &discriminator); err != nil {
+		return Either[L, R]{}, fmt.Errorf("opt: either discriminator: %w", err)
+	}
+
+	var payload []byte
+
+	if tag.Inline {
+		// the discriminator is not a part of the payload
+		delete(fields, tag.Key)
+
+		if payload, err = json.Marshal(fields); err != nil {
+			return Either[L, R]{}, err
+		}
+	} else if payload, ok = fields[tag.Value]; !ok {
+		return Either[L, R]{}, fmt.Errorf("opt: either value key %q is missing", tag.Value)
+	}
+
+	switch discriminator {
+	case tag.Left:
+		var value L
+
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return Either[L, R]{}, err
+		}
+
+		return Left[L, R](value), nil
+	case tag.Right:
+		var value R
+
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return Either[L, R]{}, err
+		}
+
+		return Right[L](value), nil
+	default:
+		return Either[L, R]{}, fmt.Errorf("opt: unknown either discriminator %q", discriminator)
+	}
+}
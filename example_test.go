@@ -1,7 +1,13 @@
 package opt
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strconv"
 )
 
 func ExampleOpt_IsExplicit() {
@@ -338,6 +344,59 @@ func ExampleFromTuple() {
 	// None
 }
 
+func ExampleFromErr() {
+	fmt.Println(FromErr(strconv.Atoi("42")))
+	fmt.Println(FromErr(strconv.Atoi("forty two")))
+
+	// Output:
+	// Some(42)
+	// None
+}
+
+func ExampleCast() {
+	values := []any{42, "42", nil}
+
+	for _, v := range values {
+		fmt.Println(Cast[int](v))
+	}
+
+	// Output:
+	// Some(42)
+	// None
+	// None
+}
+
+func ExampleAsError() {
+	_, err := os.Open("missing.txt")
+
+	err = fmt.Errorf("load config: %w", err)
+
+	if pathErr, ok := AsError[*fs.PathError](err).TryGet(); ok {
+		fmt.Println(pathErr.Op, pathErr.Path)
+	}
+
+	fmt.Println(AsError[*url.Error](err))
+
+	// Output:
+	// open missing.txt
+	// None
+}
+
+func ExampleErrorsOfType() {
+	_, err1 := strconv.Atoi("x")
+	_, err2 := strconv.ParseBool("y")
+
+	err := errors.Join(err1, errors.New("other"), err2)
+
+	for _, e := range ErrorsOfType[*strconv.NumError](err) {
+		fmt.Println(e.Func, e.Num)
+	}
+
+	// Output:
+	// Atoi x
+	// ParseBool y
+}
+
 func ExampleFromPtr() {
 	value := 42
 
@@ -390,3 +449,288 @@ func ExampleOpt_Inspect() {
 
 	// Output: banana
 }
+
+func ExampleArray() {
+	var array Array[int]
+
+	_ = array.Scan(`{1,NULL,3}`)
+
+	fmt.Println(array)
+
+	value, _ := array.Value()
+
+	fmt.Println(value)
+
+	// Output:
+	// [Some(1) None Some(3)]
+	// {1,NULL,3}
+}
+
+func ExampleOpt_Clone() {
+	original := Some(map[string][]int{"a": {1, 2}})
+	clone := original.Clone()
+
+	clone.MustGet()["a"][0] = 100
+
+	fmt.Println(original, clone)
+
+	// Output: Some(map[a:[1 2]]) Some(map[a:[100 2]])
+}
+
+func ExampleOpt_CloneFunc() {
+	type user struct {
+		Name string
+	}
+
+	original := Some(&user{Name: "bob"})
+	clone := original.CloneFunc(func(u *user) *user {
+		copied := *u
+		return &copied
+	})
+
+	clone.MustGet().Name = "alice"
+
+	fmt.Println(original.MustGet().Name, clone.MustGet().Name)
+
+	// Output: bob alice
+}
+
+func ExampleValidate() {
+	positive := func(v int) error {
+		if v <= 0 {
+			return fmt.Errorf("%d is not positive", v)
+		}
+
+		return nil
+	}
+
+	fmt.Println(Validate(Some(-1), positive))
+	fmt.Println(Validate(Some(1), positive))
+	fmt.Println(Validate(None[int](), positive))
+
+	// Output:
+	// -1 is not positive
+	// <nil>
+	// <nil>
+}
+
+func ExampleApplyDefaults() {
+	type Config struct {
+		Host Opt[string] `default:"localhost"`
+		Port Opt[int]    `default:"8080"`
+		TLS  Opt[bool]   `default:"true"`
+	}
+
+	config := Config{
+		Port: Some(9090),
+		TLS:  None[bool](),
+	}
+
+	if err := ApplyDefaults(&config); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(config.Host, config.Port, config.TLS)
+
+	// Output: Some(localhost) Some(9090) None
+}
+
+func ExampleChain() {
+	type Address struct {
+		City *string
+	}
+
+	type User struct {
+		Address *Address
+	}
+
+	city := "Paris"
+
+	users := []*User{
+		{Address: &Address{City: &city}},
+		{Address: &Address{}},
+		{},
+		nil,
+	}
+
+	for _, user := range users {
+		trace := Then(Then(Chain(FromPtr(user)),
+			func(u User) Opt[Address] { return FromPtr(u.Address) }),
+			func(a Address) Opt[string] { return FromPtr(a.City) })
+
+		fmt.Println(trace.Opt, trace.Err())
+	}
+
+	// Output:
+	// Some(Paris) <nil>
+	// None opt: path is None at step 2 (opt.Address -> string)
+	// None opt: path is None at step 1 (opt.User -> opt.Address)
+	// None opt: path is None at the start (opt.User)
+}
+
+func ExampleRecv() {
+	ch := make(chan string, 1)
+	ch <- "job"
+	close(ch)
+
+	for {
+		job, err := Recv(context.Background(), ch)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(job)
+
+		if job.IsNone() {
+			break
+		}
+	}
+
+	// Output:
+	// Some(job)
+	// None
+}
+
+func ExampleDrain() {
+	ch := make(chan int, 3)
+
+	fmt.Println(TrySend(ch, 1), TrySend(ch, 2))
+	fmt.Println(Drain(ch))
+	fmt.Println(TryRecv(ch))
+
+	// Output:
+	// true true
+	// [1 2]
+	// None
+}
+
+func ExampleParse() {
+	port, err := Parse[uint16]("8080")
+	fmt.Println(port, err)
+
+	port, err = Parse[uint16]("")
+	fmt.Println(port, err)
+
+	_, err = Parse[uint16]("65536")
+	fmt.Println(err)
+
+	// Output:
+	// Some(8080) <nil>
+	// None <nil>
+	// opt: parse "65536" as uint16: strconv.ParseUint: parsing "65536": value out of range
+}
+
+func ExampleEither() {
+	parse := func(s string) Either[error, int] {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return Left[error, int](err)
+		}
+
+		return Right[error](n)
+	}
+
+	for _, s := range []string{"42", "x"} {
+		result := parse(s)
+
+		fmt.Println(result.RightOpt(), result.IsLeft())
+	}
+
+	// Output:
+	// Some(42) false
+	// None true
+}
+
+func ExampleMarshalEither() {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	type Problem struct {
+		Code int `json:"code"`
+	}
+
+	tag := EitherTag{Key: "kind", Left: "problem", Right: "user", Inline: true}
+
+	data, _ := MarshalEither(Right[Problem](User{Name: "bob"}), tag)
+	fmt.Println(string(data))
+
+	data, _ = MarshalEither(Left[Problem, User](Problem{Code: 404}), EitherTag{})
+	fmt.Println(string(data))
+
+	// Output:
+	// {"kind":"user","name":"bob"}
+	// {"type":"left","value":{"code":404}}
+}
+
+func ExampleMatch() {
+	greet := func(name Opt[string]) string {
+		return Match(name,
+			func(name string) string { return "Hello, " + name },
+			func() string { return "Hello, stranger" },
+		)
+	}
+
+	fmt.Println(greet(Some("Bob")))
+	fmt.Println(greet(None[string]()))
+
+	// Output:
+	// Hello, Bob
+	// Hello, stranger
+}
+
+func ExampleMatchExplicit() {
+	type Patch struct {
+		Nickname Opt[string]
+	}
+
+	describe := func(p Patch) string {
+		return MatchExplicit(p.Nickname,
+			func(s string) string { return "set nickname to " + s },
+			func() string { return "clear nickname" },
+			func() string { return "keep nickname" },
+		)
+	}
+
+	fmt.Println(describe(Patch{Nickname: Some("bob")}))
+	fmt.Println(describe(Patch{Nickname: None[string]()}))
+	fmt.Println(describe(Patch{}))
+
+	// Output:
+	// set nickname to bob
+	// clear nickname
+	// keep nickname
+}
+
+func ExampleMatchErr() {
+	for _, s := range []string{"10", "", "x"} {
+		limit, err := Parse[int](s)
+
+		fmt.Println(MatchErr(limit, err,
+			func(n int) string { return fmt.Sprint("limit ", n) },
+			func() string { return "no limit" },
+			func(err error) string { return "invalid limit" },
+		))
+	}
+
+	// Output:
+	// limit 10
+	// no limit
+	// invalid limit
+}
+
+func ExampleOpt_Switch() {
+	Some(42).Switch(
+		func(n int) { fmt.Println("got", n) },
+		func() { fmt.Println("got nothing") },
+	)
+
+	None[int]().Switch(
+		func(n int) { fmt.Println("got", n) },
+		func() { fmt.Println("got nothing") },
+	)
+
+	// Output:
+	// got 42
+	// got nothing
+}
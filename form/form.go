@@ -0,0 +1,304 @@
+// Package form encodes and decodes [url.Values] to and from structs with [opt.Opt] fields.
+//
+// Struct fields are mapped to keys with `form:"key"` tags, defaulting to the field name.
+// Fields tagged with `form:"-"` are ignored and embedded structs are flattened.
+//
+// Decoding distinguishes absent keys from empty values:
+//   - Absent key leaves the option implicit None
+//   - Empty value, e.g. `?x=`, is decoded into explicit None, except for strings, see [Decoder.EmptyAsNone]
+//   - Otherwise, value is parsed into Some
+//
+// Repeated keys are decoded into slices, e.g. `opt.Opt[[]int]`.
+// Values are parsed in the same way as by [opt.Parse], including parsers registered with [opt.RegisterParser].
+package form
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/textconv"
+)
+
+// FieldError is an error of decoding or encoding a single struct field.
+type FieldError struct {
+	// Field is the name of the struct field
+	Field string
+
+	// Key is the form key of the field
+	Key string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("form: field %s (key %q): %v", e.Field, e.Key, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is a list of field errors.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Decoder decodes [url.Values] into structs.
+type Decoder struct {
+	// EmptyAsNone decodes empty values, e.g. `?x=`, into explicit None for all types.
+	// Otherwise, only options of strings and byte slices decode empty values into Some(""),
+	// which is the only meaningful empty value, while other options are still decoded into explicit None,
+	// so that explicit None encoded by [Marshal] round-trips.
+	EmptyAsNone bool
+}
+
+// Unmarshal decodes values into the struct pointed to by v using the default [Decoder].
+func Unmarshal(values url.Values, v any) error {
+	return Decoder{}.Decode(values, v)
+}
+
+// Decode decodes values into the struct pointed to by v.
+//
+// Fields which fail to decode are reported together as [Errors].
+func (d Decoder) Decode(values url.Values, v any) error {
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("form: expected non-nil pointer to struct, got %T", v)
+	}
+
+	var errs Errors
+
+	d.decodeStruct(values, value.Elem(), &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (d Decoder) decodeStruct(values url.Values, value reflect.Value, errs *Errors) {
+	for _, f := range fields(value.Type()) {
+		field := value.FieldByIndex(f.index)
+
+		raw, ok := values[f.key]
+		if !ok {
+			continue
+		}
+
+		if err := d.decodeField(field, raw); err != nil {
+			*errs = append(*errs, &FieldError{Field: f.name, Key: f.key, Err: err})
+		}
+	}
+}
+
+func (d Decoder) decodeField(field reflect.Value, raw []string) error {
+	if !optreflect.Is(field.Type()) {
+		return decodeValue(field, raw)
+	}
+
+	isEmpty := len(raw) == 0 || len(raw) == 1 && raw[0] == ""
+
+	if isEmpty && (d.EmptyAsNone || !emptyIsValue(optreflect.Elem(field.Type()))) {
+		optreflect.SetNone(field)
+
+		return nil
+	}
+
+	value := reflect.New(optreflect.Elem(field.Type())).Elem()
+
+	if err := decodeValue(value, raw); err != nil {
+		return err
+	}
+
+	optreflect.SetSome(field, value)
+
+	return nil
+}
+
+// emptyIsValue reports whether the empty string is a value of the type, i.e. for strings and byte slices.
+func emptyIsValue(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8 || emptyIsValue(t.Elem())
+	default:
+		return false
+	}
+}
+
+func decodeValue(dst reflect.Value, raw []string) error {
+	if dst.Kind() == reflect.Slice && !textconv.CanParse(dst.Type()) {
+		slice := reflect.MakeSlice(dst.Type(), len(raw), len(raw))
+
+		for i, s := range raw {
+			if err := textconv.Parse(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+
+		dst.Set(slice)
+
+		return nil
+	}
+
+	if len(raw) != 1 {
+		return errors.New("expected a single value")
+	}
+
+	return textconv.Parse(dst, raw[0])
+}
+
+// Marshal encodes the struct (or a pointer to it) into [url.Values].
+//
+// Implicit options are omitted, explicit None is encoded as an empty value
+// and slices are encoded as repeated keys.
+// Fields which fail to encode are reported together as [Errors].
+func Marshal(v any) (url.Values, error) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form: expected struct, got %T", v)
+	}
+
+	var (
+		values = make(url.Values)
+		errs   Errors
+	)
+
+	for _, f := range fields(value.Type()) {
+		field := value.FieldByIndex(f.index)
+
+		raw, ok, err := encodeField(field)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Key: f.key, Err: err})
+
+			continue
+		}
+
+		if ok {
+			values[f.key] = raw
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return values, nil
+}
+
+func encodeField(field reflect.Value) ([]string, bool, error) {
+	if !optreflect.Is(field.Type()) {
+		raw, err := encodeValue(field)
+
+		return raw, true, err
+	}
+
+	if !optreflect.IsExplicit(field) {
+		return nil, false, nil
+	}
+
+	value, ok := optreflect.Get(field)
+	if !ok {
+		return []string{""}, true, nil
+	}
+
+	raw, err := encodeValue(value)
+
+	return raw, true, err
+}
+
+func encodeValue(value reflect.Value) ([]string, error) {
+	if value.Kind() == reflect.Slice && !textconv.CanParse(value.Type()) {
+		raw := make([]string, 0, value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			s, err := textconv.Format(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			raw = append(raw, s)
+		}
+
+		return raw, nil
+	}
+
+	s, err := textconv.Format(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{s}, nil
+}
+
+type field struct {
+	name  string
+	key   string
+	index []int
+}
+
+func fields(t reflect.Type) []field {
+	var result []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		key, tagged := f.Tag.Lookup("form")
+		key, _, _ = strings.Cut(key, ",")
+
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct && !optreflect.Is(f.Type) {
+			for _, embedded := range fields(f.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				result = append(result, embedded)
+			}
+
+			continue
+		}
+
+		if key == "-" || !f.IsExported() {
+			continue
+		}
+
+		if key == "" {
+			key = f.Name
+		}
+
+		result = append(result, field{name: f.Name, key: key, index: []int{i}})
+	}
+
+	return result
+}
@@ -0,0 +1,147 @@
+package form
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+type Page struct {
+	Limit opt.Opt[int] `form:"limit"`
+}
+
+type Query struct {
+	Page
+
+	Search  opt.Opt[string]    `form:"q"`
+	Tags    opt.Opt[[]string]  `form:"tag"`
+	IDs     opt.Opt[[]int]     `form:"id"`
+	Since   opt.Opt[time.Time] `form:"since"`
+	Timeout opt.Opt[time.Duration]
+	Plain   string `form:"plain"`
+	Ignored string `form:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	values, err := url.ParseQuery("q=&tag=a&tag=b&id=1&id=2&since=2024-01-02T03:04:05Z&Timeout=1s&plain=x&Ignored=y&limit=10")
+	require.NoError(t, err)
+
+	var query Query
+
+	err = Unmarshal(values, &query)
+	require.NoError(t, err)
+
+	require.Equal(t, Query{
+		Page:    Page{Limit: opt.Some(10)},
+		Search:  opt.Some(""),
+		Tags:    opt.Some([]string{"a", "b"}),
+		IDs:     opt.Some([]int{1, 2}),
+		Since:   opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Timeout: opt.Some(time.Second),
+		Plain:   "x",
+	}, query)
+
+	t.Run("date only", func(t *testing.T) {
+		var query Query
+
+		require.NoError(t, Unmarshal(url.Values{"since": {"2024-01-02"}}, &query))
+		require.Equal(t, opt.Some(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), query.Since)
+	})
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	t.Run("absent", func(t *testing.T) {
+		var query Query
+
+		err := Decoder{EmptyAsNone: true}.Decode(url.Values{}, &query)
+		require.NoError(t, err)
+
+		require.False(t, query.Search.IsExplicit())
+	})
+
+	t.Run("empty as none", func(t *testing.T) {
+		var query Query
+
+		err := Decoder{EmptyAsNone: true}.Decode(url.Values{"q": {""}, "limit": {""}}, &query)
+		require.NoError(t, err)
+
+		require.Equal(t, opt.None[string](), query.Search)
+		require.Equal(t, opt.None[int](), query.Limit)
+	})
+
+	t.Run("empty by default", func(t *testing.T) {
+		var query Query
+
+		err := Unmarshal(url.Values{"q": {""}, "limit": {""}, "id": {""}, "since": {""}}, &query)
+		require.NoError(t, err)
+
+		require.Equal(t, opt.Some(""), query.Search)
+		require.Equal(t, opt.None[int](), query.Limit)
+		require.Equal(t, opt.None[[]int](), query.IDs)
+		require.Equal(t, opt.None[time.Time](), query.Since)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var query Query
+
+		err := Unmarshal(url.Values{"limit": {"ten"}, "id": {"1", "x"}, "q": {"a", "b"}}, &query)
+
+		var errs Errors
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 3)
+
+		require.Equal(t, "Limit", errs[0].Field)
+		require.Equal(t, "limit", errs[0].Key)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr))
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		require.Error(t, Unmarshal(url.Values{}, Query{}))
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	query := Query{
+		Search: opt.None[string](),
+		Tags:   opt.Some([]string{"a", "b"}),
+		Since:  opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Plain:  "x",
+	}
+
+	values, err := Marshal(query)
+	require.NoError(t, err)
+
+	require.Equal(t, url.Values{
+		"q":     {""},
+		"tag":   {"a", "b"},
+		"since": {"2024-01-02T03:04:05Z"},
+		"plain": {"x"},
+	}, values)
+
+	var decoded Query
+
+	err = Decoder{EmptyAsNone: true}.Decode(values, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, query, decoded)
+
+	t.Run("default decoder", func(t *testing.T) {
+		query := Query{Page: Page{Limit: opt.None[int]()}, IDs: opt.None[[]int](), Timeout: opt.Some(time.Second)}
+
+		values, err := Marshal(query)
+		require.NoError(t, err)
+		require.Equal(t, url.Values{"limit": {""}, "id": {""}, "Timeout": {"1s"}, "plain": {""}}, values)
+
+		var decoded Query
+
+		require.NoError(t, Unmarshal(values, &decoded))
+		require.Equal(t, query, decoded)
+	})
+}
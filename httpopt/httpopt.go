@@ -0,0 +1,135 @@
+// Package httpopt provides typed [net/http] request accessors returning [opt.Opt].
+//
+// Every accessor distinguishes missing parameters from empty ones:
+//   - Missing parameter is returned as implicit None
+//   - Empty parameter is returned as explicit None
+//   - Otherwise, parameter is parsed into Some
+//
+// Values are parsed in the same way as by [opt.Parse], including parsers registered with [opt.RegisterParser].
+// Parse failures are returned as [*ParamError], which can be joined with [errors.Join]
+// and written to the client with [WriteProblem].
+package httpopt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/metafates/opt"
+	"github.com/metafates/opt/internal/textconv"
+)
+
+// Location is a part of the request where the parameter is located.
+type Location string
+
+const (
+	InQuery  Location = "query"
+	InPath   Location = "path"
+	InHeader Location = "header"
+	InCookie Location = "cookie"
+	InForm   Location = "form"
+)
+
+// ParamError is an error of parsing a request parameter.
+type ParamError struct {
+	In   Location
+	Name string
+	Err  error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("invalid %s parameter %q: %v", e.In, e.Name, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Query returns the first URL query parameter with the given name.
+func Query[T any](r *http.Request, name string) (opt.Opt[T], error) {
+	values, ok := r.URL.Query()[name]
+	if !ok || len(values) == 0 {
+		return opt.Opt[T]{}, nil
+	}
+
+	return parse[T](InQuery, name, values[0])
+}
+
+// PathValue returns the path wildcard with the given name, see [http.Request.PathValue].
+//
+// Since unmatched and empty wildcards are indistinguishable, empty wildcard is returned as implicit None.
+func PathValue[T any](r *http.Request, name string) (opt.Opt[T], error) {
+	value := r.PathValue(name)
+	if value == "" {
+		return opt.Opt[T]{}, nil
+	}
+
+	return parse[T](InPath, name, value)
+}
+
+// Header returns the first header value with the given name.
+func Header[T any](r *http.Request, name string) (opt.Opt[T], error) {
+	values := r.Header.Values(name)
+	if len(values) == 0 {
+		return opt.Opt[T]{}, nil
+	}
+
+	return parse[T](InHeader, name, values[0])
+}
+
+// Cookie returns the value of the cookie with the given name.
+func Cookie[T any](r *http.Request, name string) (opt.Opt[T], error) {
+	cookie, err := r.Cookie(name)
+	if errors.Is(err, http.ErrNoCookie) {
+		return opt.Opt[T]{}, nil
+	}
+
+	if err != nil {
+		return opt.Opt[T]{}, &ParamError{In: InCookie, Name: name, Err: err}
+	}
+
+	return parse[T](InCookie, name, cookie.Value)
+}
+
+// defaultMaxMemory is the same as used by [http.Request.FormValue].
+const defaultMaxMemory = 32 << 20
+
+// FormValue returns the first value of the form parameter with the given name,
+// including both URL query and body parameters, see [http.Request.FormValue].
+//
+// Unlike [http.Request.FormValue], the form parsing error, e.g. malformed body,
+// is returned as [*ParamError] instead of treating the parameter as missing.
+func FormValue[T any](r *http.Request, name string) (opt.Opt[T], error) {
+	if r.Form == nil {
+		// ParseMultipartForm drops ParseForm errors for non-multipart requests, so parse it first
+		if err := r.ParseForm(); err != nil {
+			return opt.Opt[T]{}, &ParamError{In: InForm, Name: name, Err: err}
+		}
+
+		if err := r.ParseMultipartForm(defaultMaxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return opt.Opt[T]{}, &ParamError{In: InForm, Name: name, Err: err}
+		}
+	}
+
+	values, ok := r.Form[name]
+	if !ok || len(values) == 0 {
+		return opt.Opt[T]{}, nil
+	}
+
+	return parse[T](InForm, name, values[0])
+}
+
+func parse[T any](in Location, name, raw string) (opt.Opt[T], error) {
+	if raw == "" {
+		return opt.None[T](), nil
+	}
+
+	var value T
+
+	if err := textconv.Parse(reflect.ValueOf(&value).Elem(), raw); err != nil {
+		return opt.Opt[T]{}, &ParamError{In: in, Name: name, Err: err}
+	}
+
+	return opt.Some(value), nil
+}
@@ -0,0 +1,191 @@
+package httpopt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+type celsius float64
+
+func TestQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?limit=10&empty=&bad=x&since=2024-01-02T00:00:00Z&until=2024-01-03&temp=36.6C", nil)
+
+	limit, err := Query[int](r, "limit")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some(10), limit)
+
+	empty, err := Query[int](r, "empty")
+	require.NoError(t, err)
+	require.Equal(t, opt.None[int](), empty)
+
+	missing, err := Query[int](r, "missing")
+	require.NoError(t, err)
+	require.False(t, missing.IsExplicit())
+
+	since, err := Query[time.Time](r, "since")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), since)
+
+	until, err := Query[time.Time](r, "until")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)), until)
+
+	opt.RegisterParser(func(s string) (celsius, error) {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(s, "C"), 64)
+
+		return celsius(f), err
+	})
+
+	temp, err := Query[celsius](r, "temp")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some(celsius(36.6)), temp)
+
+	_, err = Query[int](r, "bad")
+
+	var paramErr *ParamError
+	require.ErrorAs(t, err, &paramErr)
+	require.Equal(t, InQuery, paramErr.In)
+	require.Equal(t, "bad", paramErr.Name)
+}
+
+func TestPathValue(t *testing.T) {
+	mux := http.NewServeMux()
+
+	var id opt.Opt[int]
+
+	mux.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+
+		id, err = PathValue[int](r, "id")
+		require.NoError(t, err)
+
+		missing, err := PathValue[int](r, "missing")
+		require.NoError(t, err)
+		require.False(t, missing.IsExplicit())
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	require.Equal(t, opt.Some(42), id)
+}
+
+func TestHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Retry", "3")
+	r.Header.Set("X-Empty", "")
+
+	retry, err := Header[uint8](r, "x-retry")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some[uint8](3), retry)
+
+	empty, err := Header[uint8](r, "X-Empty")
+	require.NoError(t, err)
+	require.Equal(t, opt.None[uint8](), empty)
+
+	missing, err := Header[uint8](r, "X-Missing")
+	require.NoError(t, err)
+	require.False(t, missing.IsExplicit())
+}
+
+func TestCookie(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "dark", Value: "true"})
+
+	dark, err := Cookie[bool](r, "dark")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some(true), dark)
+
+	missing, err := Cookie[bool](r, "missing")
+	require.NoError(t, err)
+	require.False(t, missing.IsExplicit())
+}
+
+func TestFormValue(t *testing.T) {
+	body := url.Values{"name": {"bob"}, "age": {""}}.Encode()
+
+	r := httptest.NewRequest(http.MethodPost, "/?page=2", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	name, err := FormValue[string](r, "name")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some("bob"), name)
+
+	age, err := FormValue[int](r, "age")
+	require.NoError(t, err)
+	require.Equal(t, opt.None[int](), age)
+
+	page, err := FormValue[int](r, "page")
+	require.NoError(t, err)
+	require.Equal(t, opt.Some(2), page)
+
+	t.Run("malformed body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/?name=query", strings.NewReader("name=%zz"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		_, err := FormValue[string](r, "name")
+
+		var paramErr *ParamError
+
+		require.ErrorAs(t, err, &paramErr)
+		require.Equal(t, InForm, paramErr.In)
+		require.Equal(t, "name", paramErr.Name)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		w := multipart.NewWriter(&buf)
+		require.NoError(t, w.WriteField("name", "alice"))
+		require.NoError(t, w.Close())
+
+		r := httptest.NewRequest(http.MethodPost, "/", &buf)
+		r.Header.Set("Content-Type", w.FormDataContentType())
+
+		name, err := FormValue[string](r, "name")
+		require.NoError(t, err)
+		require.Equal(t, opt.Some("alice"), name)
+	})
+
+	t.Run("malformed multipart", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("garbage"))
+		r.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+
+		_, err := FormValue[string](r, "name")
+		require.Error(t, err)
+	})
+}
+
+func TestWriteProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?limit=x&since=yesterday", nil)
+
+	_, limitErr := Query[int](r, "limit")
+	_, sinceErr := Query[time.Time](r, "since")
+
+	w := httptest.NewRecorder()
+
+	WriteProblem(w, errors.Join(limitErr, sinceErr))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem Problem
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Equal(t, http.StatusBadRequest, problem.Status)
+	require.Len(t, problem.InvalidParams, 2)
+	require.Equal(t, "limit", problem.InvalidParams[0].Name)
+	require.Equal(t, InQuery, problem.InvalidParams[0].In)
+	require.Equal(t, "since", problem.InvalidParams[1].Name)
+}
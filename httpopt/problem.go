@@ -0,0 +1,97 @@
+package httpopt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Problem is a problem details object as defined in RFC 7807.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam describes a single parse failure of the [Problem].
+type InvalidParam struct {
+	Name   string   `json:"name"`
+	In     Location `json:"in"`
+	Reason string   `json:"reason"`
+}
+
+// NewProblem returns a bad request [Problem] listing all the [*ParamError]s found in err,
+// including the ones joined with [errors.Join].
+func NewProblem(err error) Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+	}
+
+	var other []error
+
+	walk(err, func(err error) {
+		var paramErr *ParamError
+
+		if errors.As(err, &paramErr) {
+			problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+				Name:   paramErr.Name,
+				In:     paramErr.In,
+				Reason: paramErr.Err.Error(),
+			})
+
+			return
+		}
+
+		other = append(other, err)
+	})
+
+	if len(other) > 0 {
+		problem.Detail = errors.Join(other...).Error()
+	} else if len(problem.InvalidParams) > 0 {
+		problem.Detail = "request parameters are invalid"
+	}
+
+	return problem
+}
+
+// WriteProblem writes a bad request response with the [Problem] for the given error
+// using "application/problem+json" content type. Nil errors are ignored.
+//
+//	limit, limitErr := httpopt.Query[int](r, "limit")
+//	since, sinceErr := httpopt.Query[time.Time](r, "since")
+//
+//	if err := errors.Join(limitErr, sinceErr); err != nil {
+//		httpopt.WriteProblem(w, err)
+//		return
+//	}
+func WriteProblem(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+
+	problem := NewProblem(err)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// walk calls f for every leaf of the joined errors tree.
+func walk(err error, f func(error)) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			walk(err, f)
+		}
+
+		return
+	}
+
+	if err != nil {
+		f(err)
+	}
+}
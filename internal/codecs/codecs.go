@@ -0,0 +1,94 @@
+// Package codecs lists the codecs options are round-tripped through
+// by the tests of the root package and by [github.com/metafates/opt/opttest].
+package codecs
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/metafates/opt/cbor"
+	"github.com/metafates/opt/msgpack"
+)
+
+// Codec encodes and decodes values.
+type Codec struct {
+	Name string
+
+	// Encode encodes the value into a byte slice or, for SQL, into a [driver.Value]
+	Encode func(src any) (any, error)
+
+	// Decode decodes the encoded value into the pointer
+	Decode func(encoded, dst any) error
+}
+
+var (
+	JSON = Codec{
+		Name:   "json",
+		Encode: func(src any) (any, error) { return json.Marshal(src) },
+		Decode: func(encoded, dst any) error { return json.Unmarshal(encoded.([]byte), dst) },
+	}
+
+	Text = Codec{
+		Name:   "text",
+		Encode: func(src any) (any, error) { return src.(encoding.TextMarshaler).MarshalText() },
+		Decode: func(encoded, dst any) error { return dst.(encoding.TextUnmarshaler).UnmarshalText(encoded.([]byte)) },
+	}
+
+	Binary = Codec{
+		Name:   "binary",
+		Encode: func(src any) (any, error) { return src.(encoding.BinaryMarshaler).MarshalBinary() },
+		Decode: func(encoded, dst any) error {
+			return dst.(encoding.BinaryUnmarshaler).UnmarshalBinary(encoded.([]byte))
+		},
+	}
+
+	Gob = Codec{
+		Name: "gob",
+		Encode: func(src any) (any, error) {
+			var buf bytes.Buffer
+
+			if err := gob.NewEncoder(&buf).Encode(src); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+		},
+		Decode: func(encoded, dst any) error { return gob.NewDecoder(bytes.NewReader(encoded.([]byte))).Decode(dst) },
+	}
+
+	CBOR = Codec{
+		Name:   "cbor",
+		Encode: func(src any) (any, error) { return cbor.Marshal(src) },
+		Decode: func(encoded, dst any) error { return cbor.Unmarshal(encoded.([]byte), dst) },
+	}
+
+	Msgpack = Codec{
+		Name:   "msgpack",
+		Encode: func(src any) (any, error) { return msgpack.Marshal(src) },
+		Decode: func(encoded, dst any) error { return msgpack.Unmarshal(encoded.([]byte), dst) },
+	}
+
+	SQL = Codec{
+		Name:   "sql",
+		Encode: func(src any) (any, error) { return src.(driver.Valuer).Value() },
+		Decode: func(encoded, dst any) error { return dst.(sql.Scanner).Scan(encoded) },
+	}
+)
+
+// All is the list of every codec.
+var All = []Codec{JSON, Text, Binary, Gob, CBOR, Msgpack, SQL}
+
+// ByName returns the codec with the given name.
+func ByName(name string) (Codec, bool) {
+	for _, c := range All {
+		if c.Name == name {
+			return c, true
+		}
+	}
+
+	return Codec{}, false
+}
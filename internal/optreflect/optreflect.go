@@ -0,0 +1,72 @@
+// Package optreflect provides access to [opt.Opt] values through reflection
+// for packages which do not know the type parameter at compile time.
+//
+// [opt.Opt]: https://pkg.go.dev/github.com/metafates/opt#Opt
+package optreflect
+
+import (
+	"reflect"
+	"strings"
+)
+
+const pkgPath = "github.com/metafates/opt"
+
+// Is reports whether the given type is an option.
+func Is(t reflect.Type) bool {
+	return t.PkgPath() == pkgPath && strings.HasPrefix(t.Name(), "Opt[")
+}
+
+// Elem returns the type of the option value.
+//
+// Panics if the type is not an option.
+func Elem(t reflect.Type) reflect.Type {
+	method, ok := t.MethodByName("TryGet")
+	if !ok || !Is(t) {
+		panic("optreflect: Elem of non-option type " + t.String())
+	}
+
+	return method.Type.Out(0)
+}
+
+// Get returns the contained value and boolean stating if the option is Some.
+func Get(option reflect.Value) (reflect.Value, bool) {
+	out := option.MethodByName("TryGet").Call(nil)
+
+	return out[0], out[1].Bool()
+}
+
+// IsExplicit reports whether the option was explicitly set.
+func IsExplicit(option reflect.Value) bool {
+	return option.MethodByName("IsExplicit").Call(nil)[0].Bool()
+}
+
+// set is registered by the opt package, see [Register].
+var set func(option, value reflect.Value, some bool)
+
+// Register registers the function which sets the addressable option
+// to Some with the given value or to explicit None.
+//
+// It is called by the opt package on init, so that options could be set
+// without exposing mutating methods.
+func Register(f func(option, value reflect.Value, some bool)) {
+	set = f
+}
+
+// SetSome sets the addressable option to Some with the given value.
+func SetSome(option reflect.Value, value reflect.Value) {
+	set(option, value, true)
+}
+
+// SetNone sets the addressable option to explicit None.
+func SetNone(option reflect.Value) {
+	set(option, reflect.Value{}, false)
+}
+
+// New returns a new option of the given type set to Some with the given value.
+func New(t reflect.Type, value reflect.Value) reflect.Value {
+	option := reflect.New(t).Elem()
+
+	SetSome(option, value)
+
+	return option
+}
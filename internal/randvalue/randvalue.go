@@ -0,0 +1,135 @@
+// Package randvalue generates random values of arbitrary types in the same way as [quick.Value] does.
+//
+// It exists because importing [testing/quick] registers command-line flags,
+// which is not acceptable for non-test packages.
+package randvalue
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+)
+
+// Generator is the same interface as [quick.Generator].
+type Generator interface {
+	Generate(rand *rand.Rand, size int) reflect.Value
+}
+
+var generatorType = reflect.TypeFor[Generator]()
+
+// Value returns an arbitrary value of the given type.
+// If the type implements the [Generator] interface, that will be used.
+//
+// Returns false if the type is not supported, e.g. channels, functions and interfaces.
+func Value(t reflect.Type, rand *rand.Rand, size int) (reflect.Value, bool) {
+	if t.Implements(generatorType) {
+		return reflect.Zero(t).Interface().(Generator).Generate(rand, size), true
+	}
+
+	size = max(size, 1)
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(rand.Int()&1 == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// SetInt truncates the value to the size of the type
+		v.SetInt(int64(rand.Uint64()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(rand.Uint64())
+	case reflect.Float32:
+		v.SetFloat(randFloat(rand, math.MaxFloat32))
+	case reflect.Float64:
+		v.SetFloat(randFloat(rand, math.MaxFloat64))
+	case reflect.Complex64:
+		v.SetComplex(complex(randFloat(rand, math.MaxFloat32), randFloat(rand, math.MaxFloat32)))
+	case reflect.Complex128:
+		v.SetComplex(complex(randFloat(rand, math.MaxFloat64), randFloat(rand, math.MaxFloat64)))
+	case reflect.String:
+		runes := make([]rune, rand.Intn(size))
+
+		for i := range runes {
+			runes[i] = rune(rand.Intn(0x10ffff))
+		}
+
+		v.SetString(string(runes))
+	case reflect.Pointer:
+		if rand.Intn(size) == 0 {
+			return v, true
+		}
+
+		elem, ok := Value(t.Elem(), rand, size)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(elem)
+	case reflect.Slice:
+		n := rand.Intn(size)
+		v.Set(reflect.MakeSlice(t, n, n))
+
+		for i := 0; i < n; i++ {
+			elem, ok := Value(t.Elem(), rand, size)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			v.Index(i).Set(elem)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem, ok := Value(t.Elem(), rand, size)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			v.Index(i).Set(elem)
+		}
+	case reflect.Map:
+		n := rand.Intn(size)
+		v.Set(reflect.MakeMapWithSize(t, n))
+
+		for i := 0; i < n; i++ {
+			key, ok := Value(t.Key(), rand, size)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			value, ok := Value(t.Elem(), rand, size)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			v.SetMapIndex(key, value)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// unexported fields are left zero as they cannot be set
+			if !t.Field(i).IsExported() {
+				continue
+			}
+
+			field, ok := Value(t.Field(i).Type, rand, size)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			v.Field(i).Set(field)
+		}
+	default:
+		return reflect.Value{}, false
+	}
+
+	return v, true
+}
+
+func randFloat(rand *rand.Rand, limit float64) float64 {
+	f := rand.Float64() * limit
+
+	if rand.Int()&1 == 1 {
+		return -f
+	}
+
+	return f
+}
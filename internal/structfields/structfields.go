@@ -0,0 +1,95 @@
+// Package structfields lists struct fields encoded by the codec packages
+// following [encoding/json] conventions for tags and embedded structs.
+package structfields
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+// Field is an encoded struct field.
+type Field struct {
+	// Name is the key of the field
+	Name string
+
+	// Index is the index sequence for [reflect.Value.FieldByIndex]
+	Index []int
+
+	// OmitEmpty is set by the `,omitempty` tag option
+	OmitEmpty bool
+}
+
+// Of returns the encoded fields of the struct type using the given tag key, e.g. "cbor".
+//
+// Fields tagged with "-" are ignored. Untagged embedded structs and pointers to them are flattened,
+// unless they are options or opaque reports true for them, e.g. for types with custom encoding.
+func Of(t reflect.Type, key string, opaque func(reflect.Type) bool) []Field {
+	var fields []Field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, tagged := f.Tag.Lookup(key)
+		name, options, _ := strings.Cut(tag, ",")
+
+		if name == "-" && options == "" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && !tagged && ft.Kind() == reflect.Struct && !optreflect.Is(ft) && !opaque(ft) {
+			for _, embedded := range Of(ft, key, opaque) {
+				embedded.Index = append([]int{i}, embedded.Index...)
+				fields = append(fields, embedded)
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, Field{
+			Name:      name,
+			Index:     []int{i},
+			OmitEmpty: options == "omitempty",
+		})
+	}
+
+	return fields
+}
+
+// ByIndexAlloc returns the nested field like [reflect.Value.FieldByIndex], allocating nil embedded pointers.
+//
+// If a nil embedded pointer can not be allocated, e.g. because it is unexported,
+// it is returned with false.
+func ByIndexAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return v, false
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
@@ -0,0 +1,245 @@
+// Package textconv converts values to and from their textual representation
+// using [encoding.TextMarshaler], [encoding.TextUnmarshaler] and [strconv].
+//
+// Parsers registered with [Register] and the [TimeLayouts] are shared by all packages,
+// so that the same string means the same value everywhere.
+package textconv
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	urlType             = reflect.TypeFor[url.URL]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = make(map[reflect.Type]func(dst reflect.Value, s string) error)
+)
+
+// TimeLayouts are tried in order when parsing [time.Time].
+var TimeLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+// Register registers the parse function for the type, replacing the previously registered one.
+// Registered parsers take precedence over the built-in ones.
+func Register(t reflect.Type, parse func(dst reflect.Value, s string) error) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	parsers[t] = parse
+}
+
+// Unregister removes the parse function of the type.
+func Unregister(t reflect.Type) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	delete(parsers, t)
+}
+
+// Registered returns the parse function registered for the type.
+func Registered(t reflect.Type) (func(dst reflect.Value, s string) error, bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	parse, ok := parsers[t]
+
+	return parse, ok
+}
+
+// ParseTime parses s with the first matching layout, returning the error of the first layout if none match.
+func ParseTime(s string, layouts []string) (time.Time, error) {
+	var firstErr error
+
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return time.Time{}, firstErr
+}
+
+// CanParse reports whether values of the given type can be parsed with [Parse].
+func CanParse(t reflect.Type) bool {
+	if _, ok := Registered(t); ok {
+		return true
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) || t == durationType || t == urlType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Pointer:
+		return CanParse(t.Elem())
+	default:
+		return false
+	}
+}
+
+// Parse parses s into the addressable value.
+func Parse(dst reflect.Value, s string) error {
+	if parse, ok := Registered(dst.Type()); ok {
+		return parse(dst, s)
+	}
+
+	if dst.Type() == timeType {
+		t, err := ParseTime(s, TimeLayouts)
+		if err != nil {
+			return err
+		}
+
+		dst.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	switch dst.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		dst.SetInt(int64(d))
+
+		return nil
+	case urlType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+
+		dst.Set(reflect.ValueOf(*u))
+
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetFloat(f)
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", dst.Type())
+		}
+
+		dst.SetBytes([]byte(s))
+	case reflect.Pointer:
+		value := reflect.New(dst.Type().Elem())
+
+		if err := Parse(value.Elem(), s); err != nil {
+			return err
+		}
+
+		dst.Set(value)
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+
+	return nil
+}
+
+// Format returns the textual representation of the value.
+func Format(v reflect.Value) (string, error) {
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+
+		return string(b), err
+	}
+
+	switch v.Type() {
+	case durationType:
+		return time.Duration(v.Int()).String(), nil
+	case urlType:
+		u := v.Interface().(url.URL)
+
+		return u.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "", nil
+		}
+
+		return Format(v.Elem())
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
@@ -0,0 +1,349 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+// Apply applies the patch to the struct pointed to by doc.
+//
+// The patch is applied atomically: if any of the operations fails, doc is left unchanged.
+//
+// Struct fields are navigated by their JSON names. Other values, such as slices and maps,
+// are updated through their JSON encoding, so the full RFC 6902 semantics apply to them,
+// e.g. "/tags/-" appends to a slice.
+//
+// The `test` operation compares JSON encodings of the values ignoring insignificant differences,
+// such as the order of object members. None is encoded as null and can be tested as such.
+func Apply(doc any, patch Patch) error {
+	rv := reflect.ValueOf(doc)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("jsonpatch: expected non-nil pointer to struct, got %T", doc)
+	}
+
+	// the patch is applied to a copy, nested structs behind pointers are copied on write
+	root := reflect.New(rv.Elem().Type()).Elem()
+	root.Set(rv.Elem())
+
+	for i, op := range patch {
+		if err := apply(root, op); err != nil {
+			return &Error{Index: i, Operation: op, Err: err}
+		}
+	}
+
+	rv.Elem().Set(root)
+
+	return nil
+}
+
+func apply(root reflect.Value, op Operation) error {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case Add, Replace:
+		if op.Value == nil {
+			return errors.New("missing value")
+		}
+
+		return update(root, path, op.Op, op.Value)
+	case Remove:
+		return update(root, path, Remove, nil)
+	case Test:
+		if op.Value == nil {
+			return errors.New("missing value")
+		}
+
+		actual, err := get(root, path)
+		if err != nil {
+			return err
+		}
+
+		equal, err := jsonEqual(actual, op.Value)
+		if err != nil {
+			return err
+		}
+
+		if !equal {
+			return ErrTestFailed
+		}
+
+		return nil
+	case Move, Copy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return err
+		}
+
+		if op.Op == Move && len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+			return errors.New("cannot move a value into its child")
+		}
+
+		value, err := get(root, from)
+		if err != nil {
+			return err
+		}
+
+		if op.Op == Move {
+			if err := update(root, from, Remove, nil); err != nil {
+				return err
+			}
+		}
+
+		return update(root, path, Add, value)
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// update applies add, remove or replace operation to the value at the path relative to v.
+func update(v reflect.Value, path []string, op Op, value json.RawMessage) error {
+	if len(path) == 0 {
+		if op == Remove {
+			return errors.New("cannot remove the whole document")
+		}
+
+		return decodeInto(v, value)
+	}
+
+	switch {
+	case optreflect.Is(v.Type()):
+		inner, ok := optreflect.Get(v)
+		if !ok {
+			return fmt.Errorf("%w: member %q of None", errNotFound, path[0])
+		}
+
+		target := reflect.New(inner.Type()).Elem()
+		target.Set(inner)
+
+		if err := update(target, path, op, value); err != nil {
+			return err
+		}
+
+		optreflect.SetSome(v, target)
+
+		return nil
+	case v.Kind() == reflect.Pointer && isStruct(v.Type().Elem()):
+		if v.IsNil() {
+			return fmt.Errorf("%w: member %q of null", errNotFound, path[0])
+		}
+
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(v.Elem())
+
+		if err := update(copied.Elem(), path, op, value); err != nil {
+			return err
+		}
+
+		v.Set(copied)
+
+		return nil
+	case isStruct(v.Type()):
+		f, ok := lookupField(v.Type(), path[0])
+		if !ok {
+			return fmt.Errorf("%w: member %q", errNotFound, path[0])
+		}
+
+		fieldValue := v.FieldByIndex(f.index)
+
+		if len(path) > 1 {
+			return update(fieldValue, path[1:], op, value)
+		}
+
+		return updateField(fieldValue, op, value)
+	default:
+		return updateEncoded(v, path, op, value)
+	}
+}
+
+// updateField applies the operation to the struct field.
+// Options with None and nil pointers to structs are treated as absent members.
+func updateField(v reflect.Value, op Op, value json.RawMessage) error {
+	exists := true
+
+	switch {
+	case optreflect.Is(v.Type()):
+		_, exists = optreflect.Get(v)
+	case v.Kind() == reflect.Pointer:
+		exists = !v.IsNil()
+	}
+
+	if !exists && op != Add {
+		return errNotFound
+	}
+
+	switch {
+	case op != Remove:
+		return decodeInto(v, value)
+	case optreflect.Is(v.Type()):
+		optreflect.SetNone(v)
+	default:
+		v.SetZero()
+	}
+
+	return nil
+}
+
+// updateEncoded applies the operation to the JSON encoding of v and decodes the result back.
+func updateEncoded(v reflect.Value, path []string, op Op, value json.RawMessage) error {
+	doc, err := decodeValue(v)
+	if err != nil {
+		return err
+	}
+
+	var decoded any
+
+	if value != nil {
+		if decoded, err = decode(value); err != nil {
+			return err
+		}
+	}
+
+	updated, err := updateValue(doc, path, op, decoded)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+
+	return decodeInto(v, encoded)
+}
+
+// get returns JSON encoding of the value at the path relative to v.
+func get(v reflect.Value, path []string) (json.RawMessage, error) {
+	if len(path) == 0 {
+		return json.Marshal(v.Interface())
+	}
+
+	switch {
+	case optreflect.Is(v.Type()):
+		inner, ok := optreflect.Get(v)
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q of None", errNotFound, path[0])
+		}
+
+		return get(inner, path)
+	case v.Kind() == reflect.Pointer && isStruct(v.Type().Elem()):
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: member %q of null", errNotFound, path[0])
+		}
+
+		return get(v.Elem(), path)
+	case isStruct(v.Type()):
+		f, ok := lookupField(v.Type(), path[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q", errNotFound, path[0])
+		}
+
+		return get(v.FieldByIndex(f.index), path[1:])
+	default:
+		doc, err := decodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(value)
+	}
+}
+
+func decodeInto(v reflect.Value, data json.RawMessage) error {
+	ptr := reflect.New(v.Type())
+
+	if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+		return err
+	}
+
+	v.Set(ptr.Elem())
+
+	return nil
+}
+
+func decodeValue(v reflect.Value) (any, error) {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(data)
+}
+
+// decode decodes JSON keeping numbers as [json.Number] to avoid precision loss.
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func jsonEqual(a, b json.RawMessage) (bool, error) {
+	x, err := decode(a)
+	if err != nil {
+		return false, err
+	}
+
+	y, err := decode(b)
+	if err != nil {
+		return false, err
+	}
+
+	return equal(x, y), nil
+}
+
+func equal(x, y any) bool {
+	switch x := x.(type) {
+	case map[string]any:
+		y, ok := y.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		y, ok := y.([]any)
+
+		return ok && slices.EqualFunc(x, y, equal)
+	case json.Number:
+		y, ok := y.(json.Number)
+		if !ok {
+			return false
+		}
+
+		// numbers are compared by value, e.g. 1 equals 1.0
+		a, _, errA := big.ParseFloat(string(x), 10, 256, big.ToNearestEven)
+		b, _, errB := big.ParseFloat(string(y), 10, 256, big.ToNearestEven)
+
+		return errA == nil && errB == nil && a.Cmp(b) == 0
+	default:
+		return x == y
+	}
+}
@@ -0,0 +1,256 @@
+// Package jsonpatch generates and applies RFC 6902 JSON Patch operations for structs with [opt.Opt] fields.
+//
+// Struct fields are addressed by JSON Pointers (RFC 6901) made of their JSON names,
+// following `json:"name"` tags. Fields tagged with `json:"-"` are ignored and untagged embedded structs are flattened.
+//
+// [Diff] maps option states to operations:
+//   - Implicit None produces no operation
+//   - Explicit None produces `remove` of a populated field, see [Differ.ReplaceNull]
+//   - Some produces `add` for an unpopulated field or `replace` for a populated one
+//
+// [Apply] treats options in the same way: None is an absent member, `remove` sets the explicit None.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+// Op is the operation type.
+type Op string
+
+const (
+	Add     Op = "add"
+	Remove  Op = "remove"
+	Replace Op = "replace"
+	Move    Op = "move"
+	Copy    Op = "copy"
+	Test    Op = "test"
+)
+
+// Operation is a single JSON Patch operation.
+type Operation struct {
+	Op    Op              `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a list of operations, which is encoded as a JSON array.
+type Patch []Operation
+
+// ErrTestFailed is returned when the `test` operation fails.
+var ErrTestFailed = errors.New("test failed")
+
+// Error is an error of applying a single operation.
+type Error struct {
+	// Index is the index of the operation in the patch
+	Index int
+
+	Operation Operation
+
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonpatch: operation %d (%s %q): %v", e.Index, e.Operation.Op, e.Operation.Path, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Differ generates patches between two structs.
+type Differ struct {
+	// ReplaceNull makes explicit None diffed as `replace` with null value instead of `remove`.
+	ReplaceNull bool
+}
+
+// Diff returns the patch which transforms from into to with the default [Differ].
+func Diff(from, to any) (Patch, error) {
+	return Differ{}.Diff(from, to)
+}
+
+// Diff returns the patch which transforms from into to.
+// Both values must be structs (or pointers to them) of the same type.
+//
+// Nested structs are diffed recursively, other values are replaced as a whole if their JSON encodings differ.
+func (d Differ) Diff(from, to any) (Patch, error) {
+	fromValue, toValue := indirect(reflect.ValueOf(from)), indirect(reflect.ValueOf(to))
+
+	if fromValue.Kind() != reflect.Struct || toValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonpatch: expected structs, got %T and %T", from, to)
+	}
+
+	if fromValue.Type() != toValue.Type() {
+		return nil, fmt.Errorf("jsonpatch: type mismatch: %T and %T", from, to)
+	}
+
+	patch := Patch{}
+
+	if err := d.diffStruct(&patch, "", fromValue, toValue); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+func (d Differ) diffStruct(patch *Patch, prefix string, from, to reflect.Value) error {
+	for _, f := range fields(from.Type()) {
+		path := prefix + "/" + escape(f.name)
+
+		if err := d.diffField(patch, path, from.FieldByIndex(f.index), to.FieldByIndex(f.index)); err != nil {
+			return fmt.Errorf("jsonpatch: field %s: %w", f.goName, err)
+		}
+	}
+
+	return nil
+}
+
+func (d Differ) diffField(patch *Patch, path string, from, to reflect.Value) error {
+	var fromOK, toOK bool
+
+	switch {
+	case optreflect.Is(to.Type()):
+		if !optreflect.IsExplicit(to) {
+			return nil
+		}
+
+		from, fromOK = optreflect.Get(from)
+		to, toOK = optreflect.Get(to)
+	case to.Kind() == reflect.Pointer && isStruct(to.Type().Elem()):
+		fromOK, toOK = !from.IsNil(), !to.IsNil()
+
+		if fromOK {
+			from = from.Elem()
+		}
+
+		if toOK {
+			to = to.Elem()
+		}
+	default:
+		fromOK, toOK = true, true
+	}
+
+	switch {
+	case !toOK && !fromOK:
+		return nil
+	case !toOK:
+		if d.ReplaceNull {
+			*patch = append(*patch, Operation{Op: Replace, Path: path, Value: json.RawMessage("null")})
+		} else {
+			*patch = append(*patch, Operation{Op: Remove, Path: path})
+		}
+
+		return nil
+	case !fromOK:
+		value, err := json.Marshal(to.Interface())
+		if err != nil {
+			return err
+		}
+
+		*patch = append(*patch, Operation{Op: Add, Path: path, Value: value})
+
+		return nil
+	case isStruct(to.Type()):
+		return d.diffStruct(patch, path, from, to)
+	}
+
+	fromJSON, err := json.Marshal(from.Interface())
+	if err != nil {
+		return err
+	}
+
+	toJSON, err := json.Marshal(to.Interface())
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(fromJSON, toJSON) {
+		*patch = append(*patch, Operation{Op: Replace, Path: path, Value: toJSON})
+	}
+
+	return nil
+}
+
+type field struct {
+	name   string
+	goName string
+	index  []int
+}
+
+// fields returns JSON fields of the struct type, flattening untagged embedded structs.
+func fields(t reflect.Type) []field {
+	var result []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, tagged := f.Tag.Lookup("json")
+		name, _, _ := strings.Cut(tag, ",")
+
+		if name == "-" && tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct {
+			for _, nested := range fields(f.Type) {
+				nested.index = append([]int{i}, nested.index...)
+				result = append(result, nested)
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		result = append(result, field{name: name, goName: f.Name, index: f.Index})
+	}
+
+	return result
+}
+
+func lookupField(t reflect.Type, name string) (field, bool) {
+	for _, f := range fields(t) {
+		if f.name == name {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+)
+
+// isStruct reports whether the type is a struct which is diffed and navigated field by field.
+func isStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	return !t.Implements(marshalerType) && !reflect.PointerTo(t).Implements(marshalerType)
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v
+}
@@ -0,0 +1,274 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metafates/opt"
+)
+
+type Address struct {
+	City opt.Opt[string] `json:"city"`
+	Zip  opt.Opt[string] `json:"zip"`
+}
+
+type Meta struct {
+	Version int `json:"version"`
+}
+
+type User struct {
+	Meta
+
+	Name     opt.Opt[string]         `json:"name"`
+	Age      opt.Opt[int]            `json:"age"`
+	Address  opt.Opt[Address]        `json:"address"`
+	Billing  *Address                `json:"billing"`
+	Tags     opt.Opt[[]string]       `json:"tags"`
+	Labels   map[string]string       `json:"labels"`
+	Weird    opt.Opt[string]         `json:"a/b~c"`
+	Settings map[string]opt.Opt[int] `json:"settings"`
+	Ignored  string                  `json:"-"`
+}
+
+func ops(t *testing.T, s string) Patch {
+	t.Helper()
+
+	var patch Patch
+
+	require.NoError(t, json.Unmarshal([]byte(s), &patch))
+
+	return patch
+}
+
+func TestDiff(t *testing.T) {
+	from := User{
+		Name:    opt.Some("bob"),
+		Age:     opt.Some(30),
+		Address: opt.Some(Address{City: opt.Some("Paris")}),
+		Tags:    opt.Some([]string{"a"}),
+	}
+
+	to := User{
+		Meta:    Meta{Version: 2},
+		Name:    opt.Some("bob"),
+		Age:     opt.None[int](),
+		Address: opt.Some(Address{City: opt.Some("Berlin"), Zip: opt.Some("10115")}),
+		Billing: &Address{City: opt.Some("Rome")},
+		Weird:   opt.Some("x"),
+		Ignored: "ignored",
+	}
+
+	patch, err := Diff(from, &to)
+	require.NoError(t, err)
+
+	want := `[
+		{"op":"replace","path":"/version","value":2},
+		{"op":"remove","path":"/age"},
+		{"op":"replace","path":"/address/city","value":"Berlin"},
+		{"op":"add","path":"/address/zip","value":"10115"},
+		{"op":"add","path":"/billing","value":{"city":"Rome","zip":null}},
+		{"op":"add","path":"/a~1b~0c","value":"x"}
+	]`
+
+	actual, err := json.Marshal(patch)
+	require.NoError(t, err)
+	require.JSONEq(t, want, string(actual))
+
+	t.Run("replace null", func(t *testing.T) {
+		patch, err := Differ{ReplaceNull: true}.Diff(from, User{Age: opt.None[int]()})
+		require.NoError(t, err)
+		require.Equal(t, Patch{{Op: Replace, Path: "/age", Value: json.RawMessage("null")}}, patch)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		doc := from
+
+		require.NoError(t, Apply(&doc, patch))
+
+		require.Equal(t, 2, doc.Version)
+		require.Equal(t, opt.None[int](), doc.Age)
+		require.Equal(t, opt.Some(Address{City: opt.Some("Berlin"), Zip: opt.Some("10115")}), doc.Address)
+		require.Equal(t, &Address{City: opt.Some("Rome"), Zip: opt.None[string]()}, doc.Billing)
+		require.Equal(t, opt.Some("x"), doc.Weird)
+		require.Equal(t, opt.Some([]string{"a"}), doc.Tags)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		patch, err := Diff(from, User{})
+		require.NoError(t, err)
+		require.Empty(t, patch)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Diff(from, 1)
+		require.Error(t, err)
+
+		_, err = Diff(from, Address{})
+		require.Error(t, err)
+	})
+}
+
+func TestApply(t *testing.T) {
+	base := func() User {
+		return User{
+			Name:     opt.Some("bob"),
+			Address:  opt.Some(Address{City: opt.Some("Paris")}),
+			Billing:  &Address{City: opt.Some("Rome")},
+			Tags:     opt.Some([]string{"a", "b"}),
+			Labels:   map[string]string{"env": "prod"},
+			Settings: map[string]opt.Opt[int]{"x": opt.Some(1)},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		patch   string
+		want    func(u *User)
+		wantErr error
+	}{
+		{
+			name:  "add to None",
+			patch: `[{"op":"add","path":"/age","value":30}]`,
+			want:  func(u *User) { u.Age = opt.Some(30) },
+		},
+		{
+			name:  "add null",
+			patch: `[{"op":"add","path":"/age","value":null}]`,
+			want:  func(u *User) { u.Age = opt.None[int]() },
+		},
+		{
+			name:  "replace nested",
+			patch: `[{"op":"replace","path":"/address/city","value":"Berlin"}]`,
+			want:  func(u *User) { u.Address = opt.Some(Address{City: opt.Some("Berlin")}) },
+		},
+		{
+			name:  "remove",
+			patch: `[{"op":"remove","path":"/name"},{"op":"remove","path":"/billing"}]`,
+			want: func(u *User) {
+				u.Name = opt.None[string]()
+				u.Billing = nil
+			},
+		},
+		{
+			name:  "slice",
+			patch: `[{"op":"add","path":"/tags/-","value":"c"},{"op":"add","path":"/tags/0","value":"z"},{"op":"remove","path":"/tags/1"}]`,
+			want:  func(u *User) { u.Tags = opt.Some([]string{"z", "b", "c"}) },
+		},
+		{
+			name:  "map",
+			patch: `[{"op":"add","path":"/labels/team","value":"core"},{"op":"replace","path":"/settings/x","value":null}]`,
+			want: func(u *User) {
+				u.Labels = map[string]string{"env": "prod", "team": "core"}
+				u.Settings = map[string]opt.Opt[int]{"x": opt.None[int]()}
+			},
+		},
+		{
+			name:  "move and copy",
+			patch: `[{"op":"copy","from":"/name","path":"/a~1b~0c"},{"op":"move","from":"/address/city","path":"/billing/zip"}]`,
+			want: func(u *User) {
+				u.Weird = opt.Some("bob")
+				u.Address = opt.Some(Address{City: opt.None[string]()})
+				u.Billing = &Address{City: opt.Some("Rome"), Zip: opt.Some("Paris")}
+			},
+		},
+		{
+			name:  "test",
+			patch: `[{"op":"test","path":"/name","value":"bob"},{"op":"test","path":"/age","value":null},{"op":"test","path":"/address","value":{"zip":null,"city":"Paris"}},{"op":"test","path":"/settings/x","value":1.0}]`,
+			want:  func(*User) {},
+		},
+		{
+			name:    "test failed",
+			patch:   `[{"op":"replace","path":"/name","value":"alice"},{"op":"test","path":"/name","value":"bob"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "replace None",
+			patch:   `[{"op":"replace","path":"/age","value":1}]`,
+			wantErr: errNotFound,
+		},
+		{
+			name:    "remove None",
+			patch:   `[{"op":"remove","path":"/age"}]`,
+			wantErr: errNotFound,
+		},
+		{
+			name:    "nested in nil",
+			patch:   `[{"op":"add","path":"/billing/city","value":"x"},{"op":"remove","path":"/billing"},{"op":"add","path":"/billing/city","value":"x"}]`,
+			wantErr: errNotFound,
+		},
+		{
+			name:    "unknown member",
+			patch:   `[{"op":"add","path":"/missing","value":1}]`,
+			wantErr: errNotFound,
+		},
+		{
+			name:    "index out of range",
+			patch:   `[{"op":"replace","path":"/tags/2","value":"x"}]`,
+			wantErr: errNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := base()
+
+			err := Apply(&doc, ops(t, tc.patch))
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				require.Equal(t, base(), doc, "document must be left unchanged")
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			want := base()
+			tc.want(&want)
+
+			require.Equal(t, want, doc)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		doc := base()
+
+		for _, patch := range []string{
+			`[{"op":"add","path":"name","value":1}]`,
+			`[{"op":"add","path":"/name"}]`,
+			`[{"op":"add","path":"/name~2","value":"x"}]`,
+			`[{"op":"add","path":"/tags/01","value":"x"}]`,
+			`[{"op":"move","from":"/address","path":"/address/city"}]`,
+			`[{"op":"remove","path":""}]`,
+			`[{"op":"unknown","path":"/name"}]`,
+			`[{"op":"add","path":"/age","value":"x"}]`,
+		} {
+			var patchErr *Error
+			require.ErrorAs(t, Apply(&doc, ops(t, patch)), &patchErr, patch)
+		}
+
+		require.Error(t, Apply(doc, nil))
+	})
+
+	t.Run("does not alias", func(t *testing.T) {
+		original := base()
+		doc := original
+
+		require.NoError(t, Apply(&doc, ops(t, `[{"op":"replace","path":"/billing/city","value":"Milan"}]`)))
+
+		require.Equal(t, opt.Some("Rome"), original.Billing.City)
+		require.Equal(t, opt.Some("Milan"), doc.Billing.City)
+	})
+}
+
+func TestParsePointer(t *testing.T) {
+	tokens, err := parsePointer("/a~1b/c~0d/~01/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b", "c~d", "~1", ""}, tokens)
+
+	tokens, err = parsePointer("")
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+}
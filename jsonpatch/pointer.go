@@ -0,0 +1,178 @@
+package jsonpatch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var errNotFound = errors.New("path not found")
+
+var (
+	unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+	escaper   = strings.NewReplacer("~", "~0", "/", "~1")
+)
+
+// escape escapes the reference token of JSON Pointer.
+func escape(token string) string {
+	return escaper.Replace(token)
+}
+
+// parsePointer splits JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid escape in JSON pointer %q", pointer)
+			}
+		}
+
+		tokens[i] = unescaper.Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses the array index token. The "-" token and the index equal to the length
+// are only allowed when appending.
+func arrayIndex(token string, length int, appending bool) (int, error) {
+	if token == "-" {
+		if !appending {
+			return 0, fmt.Errorf("%w: index - refers to a nonexistent element", errNotFound)
+		}
+
+		return length, nil
+	}
+
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	limit := length
+	if appending {
+		limit++
+	}
+
+	if index >= limit {
+		return 0, fmt.Errorf("%w: index %d is out of range", errNotFound, index)
+	}
+
+	return index, nil
+}
+
+// getValue returns the value of the decoded JSON document at the path.
+func getValue(doc any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch d := doc.(type) {
+		case map[string]any:
+			value, ok := d[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q", errNotFound, token)
+			}
+
+			doc = value
+		case []any:
+			index, err := arrayIndex(token, len(d), false)
+			if err != nil {
+				return nil, err
+			}
+
+			doc = d[index]
+		default:
+			return nil, fmt.Errorf("%w: cannot reference %q in a scalar value", errNotFound, token)
+		}
+	}
+
+	return doc, nil
+}
+
+// updateValue applies add, remove or replace operation to the decoded JSON document
+// and returns the updated document.
+func updateValue(doc any, tokens []string, op Op, value any) (any, error) {
+	if len(tokens) == 0 {
+		if op == Remove {
+			return nil, errors.New("cannot remove the whole document")
+		}
+
+		return value, nil
+	}
+
+	token, rest := tokens[0], tokens[1:]
+
+	switch d := doc.(type) {
+	case map[string]any:
+		current, ok := d[token]
+
+		if len(rest) > 0 || op != Add {
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q", errNotFound, token)
+			}
+		}
+
+		if len(rest) == 0 {
+			if op == Remove {
+				delete(d, token)
+			} else {
+				d[token] = value
+			}
+
+			return d, nil
+		}
+
+		updated, err := updateValue(current, rest, op, value)
+		if err != nil {
+			return nil, err
+		}
+
+		d[token] = updated
+
+		return d, nil
+	case []any:
+		index, err := arrayIndex(token, len(d), len(rest) == 0 && op == Add)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rest) > 0 {
+			updated, err := updateValue(d[index], rest, op, value)
+			if err != nil {
+				return nil, err
+			}
+
+			d[index] = updated
+
+			return d, nil
+		}
+
+		switch op {
+		case Add:
+			d = append(d, nil)
+			copy(d[index+1:], d[index:])
+			d[index] = value
+		case Remove:
+			d = append(d[:index], d[index+1:]...)
+		default:
+			d[index] = value
+		}
+
+		return d, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot reference %q in a scalar value", errNotFound, token)
+	}
+}
@@ -0,0 +1,50 @@
+package opt
+
+// Match returns the result of calling `some` with the contained value if the option is [Some]
+// or calling `none` otherwise.
+func Match[T, R any](option Opt[T], some func(T) R, none func() R) R {
+	if option.hasValue {
+		return some(option.value)
+	}
+
+	return none()
+}
+
+// MatchExplicit works like [Match], but separates explicit [None] from the unset option, see [Opt.IsExplicit]:
+//   - `some` is called with the contained value if the option is [Some]
+//   - `none` is called if the option is explicit [None]
+//   - `unset` is called if the option is implicit [None]
+func MatchExplicit[T, R any](option Opt[T], some func(T) R, none func() R, unset func() R) R {
+	switch {
+	case option.hasValue:
+		return some(option.value)
+	case option.explicit:
+		return none()
+	default:
+		return unset()
+	}
+}
+
+// MatchErr works like [Match] for functions returning both option and error,
+// such as [Parse] or [Recv]:
+//   - `failed` is called with the error if it is not nil
+//   - `some` is called with the contained value if the option is [Some]
+//   - `none` is called otherwise
+func MatchErr[T, R any](option Opt[T], err error, some func(T) R, none func() R, failed func(error) R) R {
+	if err != nil {
+		return failed(err)
+	}
+
+	return Match(option, some, none)
+}
+
+// Switch calls `some` with the contained value if the option is [Some] or calls `none` otherwise.
+//
+// See [Match] if you need to return a value.
+func (o Opt[T]) Switch(some func(T), none func()) {
+	if o.hasValue {
+		some(o.value)
+	} else {
+		none()
+	}
+}
@@ -0,0 +1,667 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/structfields"
+)
+
+// maxDepth limits nesting of decoded objects to avoid stack exhaustion on malicious input.
+const maxDepth = 10000
+
+type kind int
+
+const (
+	kindNil kind = iota
+	kindBool
+	kindInt
+	kindUint
+	kindFloat32
+	kindFloat64
+	kindStr
+	kindBin
+	kindArray
+	kindMap
+	kindExt
+)
+
+func (k kind) String() string {
+	return [...]string{"nil", "bool", "int", "uint", "float32", "float64", "str", "bin", "array", "map", "ext"}[k]
+}
+
+// token is a decoded object header. Content of str, bin and ext is included, while
+// elements of arrays and maps follow it.
+type token struct {
+	kind    kind
+	b       bool
+	i       int64
+	u       uint64
+	f       float64
+	n       int
+	data    []byte
+	extType int8
+}
+
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (d *decoder) syntaxError(msg string) error {
+	return &SyntaxError{Offset: d.pos, msg: msg}
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, d.syntaxError("unexpected end of data")
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+
+	return b, nil
+}
+
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *decoder) next() (token, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return token{}, err
+	}
+
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return token{kind: kindInt, i: int64(c)}, nil
+	case c >= 0xe0:
+		return token{kind: kindInt, i: int64(int8(c))}, nil
+	case c&0xf0 == 0x80:
+		return token{kind: kindMap, n: int(c & 0x0f)}, nil
+	case c&0xf0 == 0x90:
+		return token{kind: kindArray, n: int(c & 0x0f)}, nil
+	case c&0xe0 == 0xa0:
+		return d.content(kindStr, int(c&0x1f))
+	}
+
+	switch c {
+	case Nil:
+		return token{kind: kindNil}, nil
+	case 0xc2, 0xc3:
+		return token{kind: kindBool, b: c == 0xc3}, nil
+	case 0xc4, 0xc5, 0xc6:
+		return d.sizedContent(kindBin, 1<<(c-0xc4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.readUint(1 << (c - 0xc7))
+		if err != nil {
+			return token{}, err
+		}
+
+		return d.extContent(int(n))
+	case 0xca:
+		u, err := d.readUint(4)
+
+		return token{kind: kindFloat32, f: float64(math.Float32frombits(uint32(u)))}, err
+	case 0xcb:
+		u, err := d.readUint(8)
+
+		return token{kind: kindFloat64, f: math.Float64frombits(u)}, err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.readUint(1 << (c - 0xcc))
+
+		return token{kind: kindUint, u: u}, err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+
+		u, err := d.readUint(size)
+		if err != nil {
+			return token{}, err
+		}
+
+		// sign extension
+		shift := 64 - 8*size
+
+		return token{kind: kindInt, i: int64(u<<shift) >> shift}, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.extContent(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		return d.sizedContent(kindStr, 1<<(c-0xd9))
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (c - 0xdc))
+
+		return token{kind: kindArray, n: int(n)}, err
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (c - 0xde))
+
+		return token{kind: kindMap, n: int(n)}, err
+	default:
+		d.pos--
+
+		return token{}, d.syntaxError("invalid format")
+	}
+}
+
+func (d *decoder) sizedContent(k kind, size int) (token, error) {
+	n, err := d.readUint(size)
+	if err != nil {
+		return token{}, err
+	}
+
+	if n > uint64(len(d.data)) {
+		return token{}, d.syntaxError("unexpected end of data")
+	}
+
+	return d.content(k, int(n))
+}
+
+func (d *decoder) content(k kind, n int) (token, error) {
+	data, err := d.read(n)
+
+	return token{kind: k, n: n, data: data}, err
+}
+
+func (d *decoder) extContent(n int) (token, error) {
+	typ, err := d.read(1)
+	if err != nil {
+		return token{}, err
+	}
+
+	data, err := d.read(n)
+
+	return token{kind: kindExt, n: n, data: data, extType: int8(typ[0])}, err
+}
+
+// skip skips a single object validating that it is well-formed.
+func (d *decoder) skip() error {
+	if d.depth++; d.depth > maxDepth {
+		return d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	t, err := d.next()
+	if err != nil {
+		return err
+	}
+
+	items := t.n
+	if t.kind == kindMap {
+		items *= 2
+	} else if t.kind != kindArray {
+		return nil
+	}
+
+	for range items {
+		if err := d.skip(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) decode(v reflect.Value) error {
+	start := d.pos
+
+	if optreflect.Is(v.Type()) {
+		return d.decodeOption(v)
+	}
+
+	if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(unmarshalerType) {
+		if err := d.skip(); err != nil {
+			return err
+		}
+
+		return v.Addr().Interface().(Unmarshaler).UnmarshalMsgpack(d.data[start:d.pos])
+	}
+
+	if d.depth++; d.depth > maxDepth {
+		return d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	if d.pos < len(d.data) && d.data[d.pos] == Nil {
+		d.pos++
+
+		v.SetZero()
+
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return d.decode(v.Elem())
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		value, err := d.decodeAny()
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+
+		return nil
+	}
+
+	t, err := d.next()
+	if err != nil {
+		return err
+	}
+
+	typeError := &UnmarshalTypeError{Value: t.kind.String(), Type: v.Type()}
+
+	switch v.Type() {
+	case timeType:
+		if t.kind != kindExt || t.extType != timestampExt {
+			return typeError
+		}
+
+		at, err := decodeTimestamp(t.data)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(at))
+
+		return nil
+	case extType:
+		if t.kind != kindExt {
+			return typeError
+		}
+
+		v.Set(reflect.ValueOf(Ext{Type: t.extType, Data: bytes.Clone(t.data)}))
+
+		return nil
+	}
+
+	if t.kind == kindStr || t.kind == kindBin {
+		if ok, err := decodeText(t, v); ok {
+			return err
+		}
+	}
+
+	switch t.kind {
+	case kindBool:
+		if v.Kind() != reflect.Bool {
+			return typeError
+		}
+
+		v.SetBool(t.b)
+	case kindInt, kindUint:
+		return decodeInt(t, v, typeError)
+	case kindFloat32, kindFloat64:
+		if v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64 {
+			return typeError
+		}
+
+		v.SetFloat(t.f)
+	case kindStr, kindBin:
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(string(t.data))
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetBytes(bytes.Clone(t.data))
+		case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetZero()
+			reflect.Copy(v, reflect.ValueOf(t.data))
+		default:
+			return typeError
+		}
+	case kindArray:
+		return d.decodeArray(t, v, typeError)
+	case kindMap:
+		return d.decodeMap(t, v, typeError)
+	default:
+		return typeError
+	}
+
+	return nil
+}
+
+// decodeOption decodes nil into explicit None and anything else into Some.
+func (d *decoder) decodeOption(v reflect.Value) error {
+	if d.pos < len(d.data) && d.data[d.pos] == Nil {
+		d.pos++
+
+		optreflect.SetNone(v)
+
+		return nil
+	}
+
+	value := reflect.New(optreflect.Elem(v.Type())).Elem()
+
+	if err := d.decode(value); err != nil {
+		return err
+	}
+
+	optreflect.SetSome(v, value)
+
+	return nil
+}
+
+// decodeText decodes the str or bin with [encoding.TextUnmarshaler] or [encoding.BinaryUnmarshaler]
+// and reports whether the value implements either of them.
+func decodeText(t token, v reflect.Value) (bool, error) {
+	if !v.CanAddr() {
+		return false, nil
+	}
+
+	ptr := v.Addr().Interface()
+
+	textUnmarshaler, isText := ptr.(encoding.TextUnmarshaler)
+	binaryUnmarshaler, isBinary := ptr.(encoding.BinaryUnmarshaler)
+
+	switch {
+	case isText && (t.kind == kindStr || !isBinary):
+		return true, textUnmarshaler.UnmarshalText(bytes.Clone(t.data))
+	case isBinary:
+		return true, binaryUnmarshaler.UnmarshalBinary(bytes.Clone(t.data))
+	default:
+		return false, nil
+	}
+}
+
+func decodeInt(t token, v reflect.Value, typeError error) error {
+	negative := t.kind == kindInt && t.i < 0
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := t.i
+
+		if t.kind == kindUint {
+			if t.u > math.MaxInt64 {
+				return typeError
+			}
+
+			n = int64(t.u)
+		}
+
+		if v.OverflowInt(n) {
+			return typeError
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if negative {
+			return typeError
+		}
+
+		n := t.u
+		if t.kind == kindInt {
+			n = uint64(t.i)
+		}
+
+		if v.OverflowUint(n) {
+			return typeError
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if t.kind == kindUint {
+			v.SetFloat(float64(t.u))
+		} else {
+			v.SetFloat(float64(t.i))
+		}
+	default:
+		return typeError
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeArray(t token, v reflect.Value, typeError error) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		if t.n > len(d.data)-d.pos {
+			return d.syntaxError("unexpected end of data")
+		}
+
+		v.Set(reflect.MakeSlice(v.Type(), t.n, t.n))
+	case reflect.Array:
+		v.SetZero()
+	default:
+		return typeError
+	}
+
+	for i := range t.n {
+		if i >= v.Len() {
+			if err := d.skip(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := d.decode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeMap(t token, v reflect.Value, typeError error) error {
+	var fields map[string]structfields.Field
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+	case reflect.Struct:
+		fields = make(map[string]structfields.Field)
+
+		for _, f := range structFields(v.Type()) {
+			fields[f.Name] = f
+		}
+	default:
+		return typeError
+	}
+
+	for range t.n {
+		if v.Kind() == reflect.Map {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decode(key); err != nil {
+				return err
+			}
+
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(value); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(key, value)
+
+			continue
+		}
+
+		var name string
+		if err := d.decode(reflect.ValueOf(&name).Elem()); err != nil {
+			return err
+		}
+
+		f, ok := fields[name]
+		if !ok {
+			if err := d.skip(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fieldValue, ok := structfields.ByIndexAlloc(v, f.Index)
+		if !ok {
+			return &UnmarshalTypeError{Value: "map", Type: fieldValue.Type()}
+		}
+
+		if err := d.decode(fieldValue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeAny decodes the next object into a generic Go value:
+//   - integers into int64, or uint64 if it does not fit
+//   - floats into float64
+//   - bin into []byte and str into string
+//   - arrays into []any
+//   - maps into map[string]any if all the keys are strings, map[any]any otherwise
+//   - timestamps into time.Time in UTC, other extension types into [Ext]
+//   - nil into nil
+func (d *decoder) decodeAny() (any, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, d.syntaxError("exceeded max nesting depth")
+	}
+
+	defer func() { d.depth-- }()
+
+	t, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.kind {
+	case kindNil:
+		return nil, nil
+	case kindBool:
+		return t.b, nil
+	case kindInt:
+		return t.i, nil
+	case kindUint:
+		if t.u > math.MaxInt64 {
+			return t.u, nil
+		}
+
+		return int64(t.u), nil
+	case kindFloat32, kindFloat64:
+		return t.f, nil
+	case kindStr:
+		return string(t.data), nil
+	case kindBin:
+		return bytes.Clone(t.data), nil
+	case kindExt:
+		if t.extType == timestampExt {
+			return decodeTimestamp(t.data)
+		}
+
+		return Ext{Type: t.extType, Data: bytes.Clone(t.data)}, nil
+	case kindArray:
+		items := make([]any, 0, min(t.n, len(d.data)-d.pos))
+
+		for range t.n {
+			item, err := d.decodeAny()
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	default:
+		return d.decodeAnyMap(t)
+	}
+}
+
+func (d *decoder) decodeAnyMap(t token) (any, error) {
+	var (
+		keys   []any
+		values []any
+	)
+
+	stringKeys := true
+
+	for range t.n {
+		key, err := d.decodeAny()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decodeAny()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := key.(string); !ok {
+			stringKeys = false
+		}
+
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, &UnmarshalTypeError{Value: "map key", Type: reflect.TypeOf(key)}
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	if stringKeys {
+		m := make(map[string]any, len(keys))
+
+		for i, key := range keys {
+			m[key.(string)] = values[i]
+		}
+
+		return m, nil
+	}
+
+	m := make(map[any]any, len(keys))
+
+	for i, key := range keys {
+		m[key] = values[i]
+	}
+
+	return m, nil
+}
+
+func decodeTimestamp(data []byte) (time.Time, error) {
+	switch len(data) {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		n := binary.BigEndian.Uint64(data)
+
+		return time.Unix(int64(n&(1<<34-1)), int64(n>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		sec := int64(binary.BigEndian.Uint64(data[4:]))
+
+		return time.Unix(sec, int64(nsec)).UTC(), nil
+	default:
+		return time.Time{}, &UnmarshalTypeError{Value: "timestamp", Type: timeType}
+	}
+}
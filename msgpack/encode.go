@@ -0,0 +1,389 @@
+package msgpack
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+	"github.com/metafates/opt/internal/structfields"
+)
+
+var (
+	marshalerType         = reflect.TypeFor[Marshaler]()
+	unmarshalerType       = reflect.TypeFor[Unmarshaler]()
+	textUnmarshalerType   = reflect.TypeFor[encoding.TextUnmarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+	timeType              = reflect.TypeFor[time.Time]()
+	extType               = reflect.TypeFor[Ext]()
+)
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, Nil)
+
+		return nil
+	}
+
+	if optreflect.Is(v.Type()) {
+		return e.option(v)
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			e.buf = append(e.buf, Nil)
+
+			return nil
+		}
+
+		data, err := v.Interface().(Marshaler).MarshalMsgpack()
+		if err != nil {
+			return fmt.Errorf("msgpack: error calling MarshalMsgpack for type %s: %w", v.Type(), err)
+		}
+
+		if !Valid(data) {
+			return fmt.Errorf("msgpack: MarshalMsgpack for type %s returned malformed data", v.Type())
+		}
+
+		e.buf = append(e.buf, data...)
+
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		e.time(v.Interface().(time.Time))
+
+		return nil
+	case extType:
+		ext := v.Interface().(Ext)
+		e.ext(ext.Type, ext.Data)
+
+		return nil
+	}
+
+	// types keeping their state in unexported fields, e.g. netip.Addr, are encoded as str or bin
+	if m, ok := implementation[encoding.TextMarshaler](v); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return fmt.Errorf("msgpack: error calling MarshalText for type %s: %w", v.Type(), err)
+		}
+
+		e.str(string(text))
+
+		return nil
+	}
+
+	if m, ok := implementation[encoding.BinaryMarshaler](v); ok {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("msgpack: error calling MarshalBinary for type %s: %w", v.Type(), err)
+		}
+
+		e.bin(data)
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.uint(v.Uint())
+	case reflect.Float32:
+		e.buf = append(e.buf, 0xca)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		e.buf = append(e.buf, 0xcb)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.str(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, Nil)
+
+			return nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.bin(v.Bytes())
+
+			return nil
+		}
+
+		return e.array(v)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.bin(b)
+
+			return nil
+		}
+
+		return e.array(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf = append(e.buf, Nil)
+
+			return nil
+		}
+
+		e.mapHeader(v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := e.encode(iter.Key()); err != nil {
+				return err
+			}
+
+			if err := e.encode(iter.Value()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return e.structure(v)
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, Nil)
+
+			return nil
+		}
+
+		return e.encode(v.Elem())
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// option encodes Some as its value and None as nil.
+func (e *encoder) option(v reflect.Value) error {
+	if value, ok := optreflect.Get(v); ok {
+		return e.encode(value)
+	}
+
+	e.buf = append(e.buf, Nil)
+
+	return nil
+}
+
+func (e *encoder) array(v reflect.Value) error {
+	n := v.Len()
+
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xdc), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdd), uint32(n))
+	}
+
+	for i := 0; i < n; i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) mapHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xde), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdf), uint32(n))
+	}
+}
+
+func (e *encoder) structure(v reflect.Value) error {
+	type entry struct {
+		name  string
+		value reflect.Value
+	}
+
+	fields := structFields(v.Type())
+	entries := make([]entry, 0, len(fields))
+
+	for _, f := range fields {
+		value, err := v.FieldByIndexErr(f.Index)
+		if err != nil {
+			// nil embedded pointer
+			continue
+		}
+
+		if f.OmitEmpty && value.IsZero() {
+			continue
+		}
+
+		entries = append(entries, entry{name: f.Name, value: value})
+	}
+
+	e.mapHeader(len(entries))
+
+	for _, entry := range entries {
+		e.str(entry.name)
+
+		if err := e.encode(entry.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) int(n int64) {
+	switch {
+	case n >= 0:
+		e.uint(uint64(n))
+	case n >= -32:
+		e.buf = append(e.buf, byte(n))
+	case n >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xd2), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xd3), uint64(n))
+	}
+}
+
+func (e *encoder) uint(n uint64) {
+	switch {
+	case n < 128:
+		e.buf = append(e.buf, byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xce), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xcf), n)
+	}
+}
+
+func (e *encoder) str(s string) {
+	n := len(s)
+
+	switch {
+	case n < 32:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xda), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdb), uint32(n))
+	}
+
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) bin(b []byte) {
+	n := len(b)
+
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xc5), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xc6), uint32(n))
+	}
+
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) ext(typ int8, data []byte) {
+	n := len(data)
+
+	switch {
+	case n == 1:
+		e.buf = append(e.buf, 0xd4)
+	case n == 2:
+		e.buf = append(e.buf, 0xd5)
+	case n == 4:
+		e.buf = append(e.buf, 0xd6)
+	case n == 8:
+		e.buf = append(e.buf, 0xd7)
+	case n == 16:
+		e.buf = append(e.buf, 0xd8)
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc7, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xc8), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xc9), uint32(n))
+	}
+
+	e.buf = append(e.buf, byte(typ))
+	e.buf = append(e.buf, data...)
+}
+
+// time encodes t with the timestamp extension in the smallest of 32, 64 or 96 bit formats.
+func (e *encoder) time(t time.Time) {
+	sec := t.Unix()
+	nsec := uint64(t.Nanosecond())
+
+	switch {
+	case nsec == 0 && sec >= 0 && sec <= math.MaxUint32:
+		e.ext(timestampExt, binary.BigEndian.AppendUint32(nil, uint32(sec)))
+	case sec >= 0 && sec>>34 == 0:
+		e.ext(timestampExt, binary.BigEndian.AppendUint64(nil, nsec<<34|uint64(sec)))
+	default:
+		data := binary.BigEndian.AppendUint32(nil, uint32(nsec))
+		e.ext(timestampExt, binary.BigEndian.AppendUint64(data, uint64(sec)))
+	}
+}
+
+// implementation returns the value as the interface I, using the pointer to addressable values if needed.
+// Nil pointers are not returned, so that they are encoded as nil.
+func implementation[I any](v reflect.Value) (I, bool) {
+	t := reflect.TypeFor[I]()
+
+	switch {
+	case v.Type().Implements(t):
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			break
+		}
+
+		return v.Interface().(I), true
+	case v.CanAddr() && reflect.PointerTo(v.Type()).Implements(t):
+		return v.Addr().Interface().(I), true
+	}
+
+	var zero I
+
+	return zero, false
+}
+
+func structFields(t reflect.Type) []structfields.Field {
+	return structfields.Of(t, "msgpack", func(t reflect.Type) bool {
+		ptr := reflect.PointerTo(t)
+
+		return t == timeType ||
+			ptr.Implements(unmarshalerType) ||
+			ptr.Implements(textUnmarshalerType) ||
+			ptr.Implements(binaryUnmarshalerType)
+	})
+}
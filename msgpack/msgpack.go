@@ -0,0 +1,112 @@
+// Package msgpack implements encoding and decoding of MessagePack.
+//
+// Mapping between MessagePack and Go values follows [encoding/json] conventions:
+//   - Structs are encoded as maps with field names as keys, which can be changed with `msgpack:"name"` tags.
+//     Fields tagged with `msgpack:"-"` are ignored and `msgpack:",omitempty"` omits zero values
+//   - Byte slices are encoded as bin, strings as str
+//   - Nil pointers, slices, maps and interfaces are encoded as nil
+//   - [time.Time] is encoded with the timestamp extension type
+//   - Types implementing [encoding.TextMarshaler], e.g. netip.Addr, are encoded as str
+//     and types implementing only [encoding.BinaryMarshaler], e.g. url.URL, as bin
+//   - [opt.Opt] Some is encoded as its value and None as nil. Use `msgpack:",omitempty"`
+//     struct tag to omit implicit None fields, so that they remain implicit after decoding
+//
+// Nil is decoded into zero values, unless the value implements [Unmarshaler] or is an option.
+// Types may customize their encoding by implementing [Marshaler] and [Unmarshaler].
+//
+// [opt.Opt]: https://pkg.go.dev/github.com/metafates/opt#Opt
+package msgpack
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Marshaler is the interface implemented by types that can marshal themselves into a valid MessagePack object.
+type Marshaler interface {
+	MarshalMsgpack() ([]byte, error)
+}
+
+// Unmarshaler is the interface implemented by types that can unmarshal a MessagePack object of themselves.
+//
+// The data is a single well-formed object, including nil.
+// UnmarshalMsgpack must copy the data if it wishes to retain the data after returning.
+type Unmarshaler interface {
+	UnmarshalMsgpack(data []byte) error
+}
+
+// Nil is the encoded nil object.
+const Nil byte = 0xc0
+
+// Ext is an application-specific extension type.
+// Unknown extension types are decoded into it.
+type Ext struct {
+	Type int8
+	Data []byte
+}
+
+// timestampExt is the predefined timestamp extension type.
+const timestampExt int8 = -1
+
+// Marshal returns the MessagePack encoding of v.
+func Marshal(v any) ([]byte, error) {
+	var e encoder
+
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	return e.buf, nil
+}
+
+// Unmarshal parses the MessagePack-encoded data and stores the result in the value pointed to by v.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("msgpack: Unmarshal(non-pointer %T)", v)
+	}
+
+	d := decoder{data: data}
+
+	if err := d.decode(rv.Elem()); err != nil {
+		return err
+	}
+
+	if d.pos != len(data) {
+		return &SyntaxError{Offset: d.pos, msg: "unexpected data after top-level object"}
+	}
+
+	return nil
+}
+
+// Valid reports whether data is a single well-formed MessagePack object.
+func Valid(data []byte) bool {
+	d := decoder{data: data}
+
+	return d.skip() == nil && d.pos == len(data)
+}
+
+// SyntaxError is a description of a malformed MessagePack data.
+type SyntaxError struct {
+	// Offset is the byte offset after which the error occurred
+	Offset int
+
+	msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("msgpack: %s at offset %d", e.msg, e.Offset)
+}
+
+// UnmarshalTypeError describes a MessagePack value that was not appropriate for a value of a specific Go type.
+type UnmarshalTypeError struct {
+	// Value is the description of MessagePack value, e.g. "bin"
+	Value string
+
+	// Type is the Go type it could not be assigned to
+	Type reflect.Type
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("msgpack: cannot unmarshal %s into Go value of type %s", e.Value, e.Type)
+}
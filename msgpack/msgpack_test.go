@@ -0,0 +1,259 @@
+package msgpack
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		value any
+		want  string
+	}{
+		{nil, "c0"},
+		{false, "c2"},
+		{true, "c3"},
+		{0, "00"},
+		{127, "7f"},
+		{128, "cc80"},
+		{256, "cd0100"},
+		{65536, "ce00010000"},
+		{uint64(math.MaxUint64), "cfffffffffffffffff"},
+		{-1, "ff"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{-129, "d1ff7f"},
+		{-32769, "d2ffff7fff"},
+		{int64(math.MinInt64), "d38000000000000000"},
+		{float32(1.5), "ca3fc00000"},
+		{1.5, "cb3ff8000000000000"},
+		{"", "a0"},
+		{"a", "a161"},
+		{strings.Repeat("a", 32), "d920" + strings.Repeat("61", 32)},
+		{[]byte{1, 2}, "c4020102"},
+		{[]int{1, 2, 3}, "93010203"},
+		{map[string]int{"a": 1}, "81a16101"},
+		{Ext{Type: 5, Data: []byte{1}}, "d40501"},
+		{Ext{Type: 5, Data: []byte{1, 2, 3}}, "c70305010203"},
+		{time.Unix(1, 0), "d6ff00000001"},
+		{time.Unix(1, 1), "d7ff0000000400000001"},
+		{time.Unix(-1, 0), "c70cff00000000ffffffffffffffff"},
+	}
+
+	for _, tc := range testCases {
+		got, err := Marshal(tc.value)
+		require.NoError(t, err, "%#v", tc.value)
+		require.Equal(t, tc.want, hex.EncodeToString(got), "%#v", tc.value)
+	}
+}
+
+type Inner struct {
+	Value int `msgpack:"v"`
+}
+
+type Item struct {
+	Inner
+
+	Name    string            `msgpack:"name"`
+	Tags    []string          `msgpack:"tags,omitempty"`
+	Data    []byte            `msgpack:"data"`
+	Score   float64           `msgpack:"score"`
+	At      time.Time         `msgpack:"at"`
+	Ptr     *int              `msgpack:"ptr"`
+	Meta    map[string]uint16 `msgpack:"meta"`
+	Ignored string            `msgpack:"-"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	n := -5
+
+	item := Item{
+		Inner: Inner{Value: 42},
+		Name:  "x",
+		Data:  []byte{1, 2},
+		Score: 1.25,
+		At:    time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Ptr:   &n,
+		Meta:  map[string]uint16{"a": 1000},
+	}
+
+	data, err := Marshal(item)
+	require.NoError(t, err)
+
+	var decoded Item
+
+	require.NoError(t, Unmarshal(data, &decoded))
+	require.Equal(t, item, decoded)
+
+	var generic any
+
+	require.NoError(t, Unmarshal(data, &generic))
+	require.Equal(t, map[string]any{
+		"v":     int64(42),
+		"name":  "x",
+		"data":  []byte{1, 2},
+		"score": 1.25,
+		"at":    item.At,
+		"ptr":   int64(-5),
+		"meta":  map[string]any{"a": int64(1000)},
+	}, generic)
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		n := 1
+		ptr := &n
+
+		require.NoError(t, Unmarshal([]byte{Nil}, &ptr))
+		require.Nil(t, ptr)
+	})
+
+	t.Run("ext", func(t *testing.T) {
+		var ext Ext
+		require.NoError(t, Unmarshal(mustHex(t, "c70305010203"), &ext))
+		require.Equal(t, Ext{Type: 5, Data: []byte{1, 2, 3}}, ext)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var n int8
+
+		var typeErr *UnmarshalTypeError
+		require.ErrorAs(t, Unmarshal(mustHex(t, "cd0100"), &n), &typeErr)
+
+		var u uint
+		require.ErrorAs(t, Unmarshal(mustHex(t, "ff"), &u), &typeErr)
+
+		var s string
+		require.ErrorAs(t, Unmarshal(mustHex(t, "01"), &s), &typeErr)
+
+		var syntaxErr *SyntaxError
+		require.ErrorAs(t, Unmarshal(mustHex(t, "a261"), &s), &syntaxErr)
+		require.ErrorAs(t, Unmarshal(mustHex(t, "0101"), &n), &syntaxErr)
+		require.ErrorAs(t, Unmarshal(mustHex(t, "c1"), &n), &syntaxErr)
+
+		require.Error(t, Unmarshal(mustHex(t, "01"), n))
+	})
+}
+
+func TestValid(t *testing.T) {
+	require.True(t, Valid(mustHex(t, "93010203")))
+	require.False(t, Valid(mustHex(t, "930102")))
+	require.False(t, Valid(mustHex(t, "c1")))
+}
+
+func TestOption(t *testing.T) {
+	type Patch struct {
+		Name  opt.Opt[string]       `msgpack:"name"`
+		Tags  opt.Opt[[]string]     `msgpack:"tags,omitempty"`
+		Age   opt.Opt[int]          `msgpack:"age,omitempty"`
+		Email opt.Opt[string]       `msgpack:"email,omitempty"`
+		Inner opt.Opt[opt.Opt[int]] `msgpack:"inner"`
+	}
+
+	patch := Patch{Name: opt.Some("bob"), Tags: opt.Some([]string{"a"}), Age: opt.None[int](), Inner: opt.Some(opt.Some(1))}
+
+	data, err := Marshal(patch)
+	require.NoError(t, err)
+
+	var decoded Patch
+
+	require.NoError(t, Unmarshal(data, &decoded))
+	require.Equal(t, patch, decoded)
+	require.True(t, decoded.Age.IsExplicit())
+	require.False(t, decoded.Email.IsExplicit())
+
+	t.Run("states", func(t *testing.T) {
+		data, err := Marshal(opt.Some(1))
+		require.NoError(t, err)
+		require.Equal(t, mustHex(t, "01"), data)
+
+		data, err = Marshal(opt.None[int]())
+		require.NoError(t, err)
+		require.Equal(t, mustHex(t, "c0"), data)
+
+		var decoded opt.Opt[int]
+
+		require.NoError(t, Unmarshal(data, &decoded))
+		require.Equal(t, opt.None[int](), decoded)
+	})
+
+	t.Run("type error", func(t *testing.T) {
+		var decoded opt.Opt[int]
+
+		require.Error(t, Unmarshal(mustHex(t, "a161"), &decoded))
+	})
+}
+
+// binaryID implements only encoding.BinaryMarshaler and keeps its state in an unexported field.
+type binaryID struct {
+	id uint16
+}
+
+func (b binaryID) MarshalBinary() ([]byte, error) {
+	return []byte{byte(b.id >> 8), byte(b.id)}, nil
+}
+
+func (b *binaryID) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return errors.New("invalid id")
+	}
+
+	b.id = uint16(data[0])<<8 | uint16(data[1])
+
+	return nil
+}
+
+func TestTextMarshaler(t *testing.T) {
+	addr := netip.MustParseAddr("1.2.3.4")
+
+	data, err := Marshal(addr)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "a7312e322e332e34"), data)
+
+	var decoded netip.Addr
+
+	require.NoError(t, Unmarshal(data, &decoded))
+	require.Equal(t, addr, decoded)
+
+	type Host struct {
+		Addr opt.Opt[netip.Addr]
+		URL  url.URL
+		ID   binaryID
+	}
+
+	host := Host{
+		Addr: opt.Some(addr),
+		URL:  url.URL{Scheme: "https", Host: "example.com"},
+		ID:   binaryID{id: 0x0102},
+	}
+
+	data, err = Marshal(host)
+	require.NoError(t, err)
+
+	var decodedHost Host
+
+	require.NoError(t, Unmarshal(data, &decodedHost))
+	require.Equal(t, host, decodedHost)
+
+	data, err = Marshal(binaryID{id: 0x0102})
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "c4020102"), data)
+}
@@ -6,6 +6,7 @@
 package opt
 
 import (
+	"errors"
 	"fmt"
 
 	"google.golang.org/protobuf/proto"
@@ -89,6 +90,60 @@ func FromTuple[T any](value T, ok bool) Opt[T] {
 	return None[T]()
 }
 
+// FromErr returns [Some] with the given value if err is nil, [None] otherwise.
+//
+// The error is dropped, use it only when the reason of failure does not matter.
+func FromErr[T any](value T, err error) Opt[T] {
+	if err == nil {
+		return Some(value)
+	}
+
+	return None[T]()
+}
+
+// Cast returns [Some] with the value asserted to the type T if possible, [None] otherwise.
+//
+// For interface types T, nil value is [None].
+func Cast[T any](value any) Opt[T] {
+	v, ok := value.(T)
+
+	return FromTuple(v, ok)
+}
+
+// AsError returns [Some] with the first error in err's tree that matches the type E, [None] otherwise.
+//
+// The tree is walked the same way as [errors.As] does, including wrapped and joined errors.
+func AsError[E error](err error) Opt[E] {
+	var target E
+
+	return FromTuple(target, errors.As(err, &target))
+}
+
+// ErrorsOfType returns all errors in err's tree that match the type E in pre-order, depth-first traversal.
+//
+// Unlike [AsError], it does not stop at the first match and returns nil if no errors match.
+func ErrorsOfType[E error](err error) []E {
+	var matches []E
+
+	walkErrors(err, func(err error) {
+		if e, ok := err.(E); ok {
+			matches = append(matches, e)
+
+			return
+		}
+
+		if as, ok := err.(interface{ As(any) bool }); ok {
+			var target E
+
+			if as.As(&target) {
+				matches = append(matches, target)
+			}
+		}
+	})
+
+	return matches
+}
+
 // IsExplicit reports whether this option was explicitly specified as either [None] or [Some].
 // This property is also applicable for decoded values, such as ones from [json.Unmarshal].
 //
@@ -150,7 +205,7 @@ func (o Opt[T]) GetOrEmpty() T {
 func (o Opt[T]) TryGet() (T, bool) {
 	// we could just return o.value, o.hasValue
 	// but if T is a pointer-value it makes it possible to modify underlying empty value for all the future calls.
-	// the risk is still there for non-empty values (unless we deep-clone), but it is usually expected behaviour
+	// the risk is still there for non-empty values (unless Opt.Clone is used), but it is usually expected behaviour
 	if o.hasValue {
 		return o.value, true
 	}
@@ -267,7 +322,8 @@ func (o Opt[T]) Filter(predicate func(T) bool) Opt[T] {
 // ToPtr returns pointer to the value if the option is [Some] or nil otherwise.
 //
 // The underlying value of the pointer is safe to modify, as it is copied before return
-// to avoid changes to the original value.
+// to avoid changes to the original value. However, pointer-like values, such as slices and maps,
+// still share memory with the original, use [Opt.Clone] to avoid that.
 func (o Opt[T]) ToPtr() *T {
 	if o.hasValue {
 		value := o.value
@@ -344,3 +400,20 @@ func AndThen[T, U any](option Opt[T], f func(T) Opt[U]) Opt[U] {
 
 	return None[U]()
 }
+
+func walkErrors(err error, f func(error)) {
+	if err == nil {
+		return
+	}
+
+	f(err)
+
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		walkErrors(err.Unwrap(), f)
+	case interface{ Unwrap() []error }:
+		for _, err := range err.Unwrap() {
+			walkErrors(err, f)
+		}
+	}
+}
@@ -1,14 +1,28 @@
 package opt
 
 import (
-	"bytes"
+	"context"
 	"database/sql/driver"
-	"encoding"
-	"encoding/gob"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"testing/quick"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/metafates/opt/internal/codecs"
+	"github.com/metafates/opt/internal/textconv"
 )
 
 func TestOpt_ToPtr(t *testing.T) {
@@ -19,6 +33,881 @@ func TestOpt_ToPtr(t *testing.T) {
 	require.Equal(t, Some("a"), x)
 }
 
+type clonable struct {
+	values []int
+}
+
+func (c *clonable) Clone() *clonable {
+	return &clonable{values: append([]int(nil), c.values...)}
+}
+
+func TestOpt_Clone(t *testing.T) {
+	t.Run("states", func(t *testing.T) {
+		require.Equal(t, Opt[[]int]{}, Opt[[]int]{}.Clone())
+		require.Equal(t, None[[]int](), None[[]int]().Clone())
+		require.Equal(t, Some([]int(nil)), Some([]int(nil)).Clone())
+		require.Equal(t, Some[any](nil), Some[any](nil).Clone())
+		require.Equal(t, Some[*clonable](nil), Some[*clonable](nil).Clone())
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		original := Some([]byte("abc"))
+		clone := original.Clone()
+
+		clone.MustGet()[0] = 'x'
+
+		require.Equal(t, Some([]byte("abc")), original)
+		require.Equal(t, Some([]byte("xbc")), clone)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		original := Some([]map[string]*clonable{{"a": {values: []int{1}}}})
+		clone := original.Clone()
+
+		clone.MustGet()[0]["a"].values[0] = 2
+		clone.MustGet()[0]["b"] = nil
+
+		require.Equal(t, []int{1}, original.MustGet()[0]["a"].values)
+		require.Len(t, original.MustGet()[0], 1)
+	})
+
+	t.Run("cloner", func(t *testing.T) {
+		original := Some(&clonable{values: []int{1}})
+		clone := original.Clone()
+
+		clone.MustGet().values[0] = 2
+
+		require.Equal(t, []int{1}, original.MustGet().values)
+	})
+
+	t.Run("option", func(t *testing.T) {
+		original := Some(Some([]int{1}))
+		clone := original.Clone()
+
+		clone.MustGet().MustGet()[0] = 2
+
+		require.Equal(t, Some(Some([]int{1})), original)
+	})
+}
+
+type defaultsServer struct {
+	Host    Opt[string]        `default:"localhost"`
+	Port    Opt[int]           `default:"8080"`
+	Timeout Opt[time.Duration] `default:"5s"`
+	Tags    Opt[[]string]      `default:"a, b"`
+	Debug   bool               `default:"true"`
+	Name    string             `default:"server"`
+	Limit   *int               `default:"10"`
+	Address Opt[string]
+}
+
+func (s *defaultsServer) SetDefaults() {
+	if !s.Address.IsExplicit() {
+		s.Address = Some(fmt.Sprintf("%s:%d", s.Host.GetOrEmpty(), s.Port.GetOrEmpty()))
+	}
+}
+
+type defaultsConfig struct {
+	Primary   defaultsServer
+	Secondary *defaultsServer
+	Fallback  Opt[defaultsServer]
+	Replicas  []defaultsServer
+	Missing   *defaultsServer
+	Created   time.Time
+}
+
+func TestApplyDefaults(t *testing.T) {
+	limit := 10
+
+	want := defaultsServer{
+		Host:    Some("localhost"),
+		Port:    Some(8080),
+		Timeout: Some(5 * time.Second),
+		Tags:    Some([]string{"a", "b"}),
+		Debug:   true,
+		Name:    "server",
+		Limit:   &limit,
+		Address: Some("localhost:8080"),
+	}
+
+	t.Run("implicit", func(t *testing.T) {
+		var server defaultsServer
+
+		require.NoError(t, ApplyDefaults(&server))
+		require.Equal(t, want, server)
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		server := defaultsServer{
+			Host:    None[string](),
+			Port:    Some(9090),
+			Name:    "custom",
+			Address: None[string](),
+		}
+
+		require.NoError(t, ApplyDefaults(&server))
+
+		require.Equal(t, None[string](), server.Host)
+		require.Equal(t, Some(9090), server.Port)
+		require.Equal(t, "custom", server.Name)
+		require.Equal(t, None[string](), server.Address)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		config := defaultsConfig{
+			Secondary: &defaultsServer{},
+			Fallback:  Some(defaultsServer{Port: Some(1)}),
+			Replicas:  make([]defaultsServer, 2),
+		}
+
+		require.NoError(t, ApplyDefaults(&config))
+
+		require.Equal(t, want, config.Primary)
+		require.Equal(t, want, *config.Secondary)
+		require.Equal(t, Some(1), config.Fallback.MustGet().Port)
+		require.Equal(t, Some("localhost:1"), config.Fallback.MustGet().Address)
+		require.Equal(t, []defaultsServer{want, want}, config.Replicas)
+		require.Nil(t, config.Missing)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.Error(t, ApplyDefaults(defaultsServer{}))
+
+		require.ErrorContains(t, ApplyDefaults(&struct {
+			Port Opt[int] `default:"x"`
+		}{}), "field Port")
+
+		require.ErrorContains(t, ApplyDefaults(&struct {
+			Ch Opt[chan int] `default:"x"`
+		}{}), "unsupported type")
+	})
+}
+
+type pathUser struct {
+	Name    string
+	Address *pathAddress
+}
+
+type pathAddress struct {
+	Lines map[string][]string
+}
+
+func TestPath(t *testing.T) {
+	address := func(u pathUser) Opt[pathAddress] { return FromPtr(u.Address) }
+	lines := func(key string) func(pathAddress) Opt[[]string] {
+		return func(a pathAddress) Opt[[]string] { return IndexMap(a.Lines)(key) }
+	}
+	first := func(lines []string) Opt[string] { return IndexSlice(lines)(0) }
+
+	user := &pathUser{
+		Name: "bob",
+		Address: &pathAddress{
+			Lines: map[string][]string{"street": {"Baker St"}, "city": {}},
+		},
+	}
+
+	t.Run("some", func(t *testing.T) {
+		trace := Path3(FromPtr(user), address, lines("street"), first)
+
+		require.Equal(t, Some("Baker St"), trace.Opt)
+		require.NoError(t, trace.Err())
+	})
+
+	t.Run("zero trace", func(t *testing.T) {
+		trace := Then(Trace[pathUser]{}, func(pathUser) Opt[pathAddress] {
+			t.Fatal("f must not be called")
+
+			return None[pathAddress]()
+		})
+
+		require.Equal(t, None[pathAddress](), trace.Opt)
+		require.EqualError(t, trace.Err(), "opt: path is None at the start (opt.pathUser)")
+	})
+
+	t.Run("none", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			user  *pathUser
+			key   string
+			step  int
+			error string
+		}{
+			{
+				name:  "nil user",
+				key:   "street",
+				step:  0,
+				error: "opt: path is None at the start (opt.pathUser)",
+			},
+			{
+				name:  "nil address",
+				user:  &pathUser{},
+				key:   "street",
+				step:  1,
+				error: "opt: path is None at step 1 (opt.pathUser -> opt.pathAddress)",
+			},
+			{
+				name:  "missing key",
+				user:  user,
+				key:   "zip",
+				step:  2,
+				error: "opt: path is None at step 2 (opt.pathAddress -> []string)",
+			},
+			{
+				name:  "empty slice",
+				user:  user,
+				key:   "city",
+				step:  3,
+				error: "opt: path is None at step 3 ([]string -> string)",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				trace := Path3(FromPtr(tc.user), address, lines(tc.key), first)
+
+				require.Equal(t, None[string](), trace.Opt)
+
+				var pathErr *PathError
+
+				require.ErrorAs(t, trace.Err(), &pathErr)
+				require.Equal(t, tc.step, pathErr.Step)
+				require.EqualError(t, pathErr, tc.error)
+			})
+		}
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		calls := 0
+		count := func(s string) Opt[int] {
+			calls++
+			return Some(len(s))
+		}
+
+		trace := Then(Path2(FromPtr(user), address, lines("zip")), first)
+		trace2 := Then(trace, count)
+
+		require.Equal(t, 0, calls)
+		require.True(t, trace2.IsNone())
+		require.EqualError(t, trace2.Err(), "opt: path is None at step 2 (opt.pathAddress -> []string)")
+
+		name := Then(Chain(FromPtr(user)), func(u pathUser) Opt[string] { return Some(u.Name) })
+		require.Equal(t, Some("bob"), name.Opt)
+
+		length := Path4(
+			FromPtr(user), address, lines("street"), first, count,
+		)
+		require.Equal(t, Some(8), length.Opt)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestTryRecv(t *testing.T) {
+	ch := make(chan int, 1)
+
+	require.Equal(t, None[int](), TryRecv(ch))
+
+	ch <- 0
+	require.Equal(t, Some(0), TryRecv(ch))
+
+	close(ch)
+	require.Equal(t, None[int](), TryRecv(ch))
+}
+
+func TestRecv(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		ch := make(chan int)
+
+		go func() { ch <- 42 }()
+
+		value, err := Recv(context.Background(), ch)
+		require.NoError(t, err)
+		require.Equal(t, Some(42), value)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		ch := make(chan int)
+
+		go close(ch)
+
+		value, err := Recv(context.Background(), ch)
+		require.NoError(t, err)
+		require.Equal(t, None[int](), value)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go cancel()
+
+		value, err := Recv(ctx, make(chan int))
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, value.IsExplicit())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		value, err := RecvTimeout(make(chan int), time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.False(t, value.IsExplicit())
+
+		ch := make(chan int, 1)
+		ch <- 1
+
+		value, err = RecvTimeout(ch, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, Some(1), value)
+
+		close(ch)
+
+		value, err = RecvTimeout(ch, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, None[int](), value)
+	})
+}
+
+func TestTrySend(t *testing.T) {
+	ch := make(chan int, 1)
+
+	require.True(t, TrySend(ch, 1))
+	require.False(t, TrySend(ch, 2))
+	require.Equal(t, 1, <-ch)
+
+	close(ch)
+	require.Panics(t, func() { TrySend(ch, 3) })
+}
+
+func TestDrain(t *testing.T) {
+	ch := make(chan int, 3)
+
+	require.Nil(t, Drain(ch))
+
+	ch <- 1
+	ch <- 2
+	require.Equal(t, []int{1, 2}, Drain(ch))
+
+	ch <- 3
+	close(ch)
+	require.Equal(t, []int{3}, Drain(ch))
+	require.Nil(t, Drain(ch))
+}
+
+func TestChan_Concurrent(t *testing.T) {
+	const (
+		producers = 4
+		values    = 100
+	)
+
+	ch := make(chan int, 8)
+
+	var wg sync.WaitGroup
+
+	for p := range producers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range values {
+				for !TrySend(ch, p*values+i) {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	seen := make(map[int]bool)
+
+	for {
+		value, err := Recv(context.Background(), ch)
+		require.NoError(t, err)
+
+		v, ok := value.TryGet()
+		if !ok {
+			break
+		}
+
+		seen[v] = true
+
+		for _, v := range Drain(ch) {
+			seen[v] = true
+		}
+	}
+
+	require.Len(t, seen, producers*values)
+}
+
+type parseLevel int
+
+type parseCelsius float64
+
+// parseLabel is registered concurrently by the tests.
+type parseLabel interface {
+	Label() string
+}
+
+// unregisterParser removes the parser registered by the test on cleanup,
+// so that the global registry is left intact for other tests.
+func unregisterParser[T any](t *testing.T) {
+	t.Cleanup(func() {
+		textconv.Unregister(reflect.TypeFor[T]())
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("numbers", func(t *testing.T) {
+		requireParse(t, "-8", Some(int8(-8)))
+		requireParse(t, "-16", Some(int16(-16)))
+		requireParse(t, "-32", Some(int32(-32)))
+		requireParse(t, "-64", Some(int64(-64)))
+		requireParse(t, "42", Some(42))
+		requireParse(t, "8", Some(uint8(8)))
+		requireParse(t, "16", Some(uint16(16)))
+		requireParse(t, "32", Some(uint32(32)))
+		requireParse(t, "64", Some(uint64(64)))
+		requireParse(t, "42", Some(uint(42)))
+		requireParse(t, "1.5", Some(float32(1.5)))
+		requireParse(t, "2.5", Some(2.5))
+		requireParse(t, "7", Some(parseLevel(7)))
+
+		_, err := Parse[int8]("128")
+		require.ErrorContains(t, err, "out of range")
+
+		_, err = Parse[uint]("-1")
+		require.Error(t, err)
+	})
+
+	t.Run("other", func(t *testing.T) {
+		requireParse(t, "true", Some(true))
+		requireParse(t, "1", Some(true))
+		requireParse(t, "t", Some(true))
+		requireParse(t, "F", Some(false))
+		requireParse(t, "hello", Some("hello"))
+		requireParse(t, "1m30s", Some(90*time.Second))
+		requireParse(t, "127.0.0.1", Some(netip.MustParseAddr("127.0.0.1")))
+		requireParse(t, "https://example.com/a?b=c", Some(url.URL{Scheme: "https", Host: "example.com", Path: "/a", RawQuery: "b=c"}))
+		requireParse(t, "2024-01-02T03:04:05Z", Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+		requireParse(t, "2024-01-02 03:04:05", Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+		requireParse(t, "2024-01-02", Some(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+		n := 5
+		requireParse(t, "5", Some(&n))
+
+		u, err := Parse[*url.URL]("/path")
+		require.NoError(t, err)
+		require.Equal(t, "/path", u.MustGet().Path)
+	})
+
+	t.Run("time layouts", func(t *testing.T) {
+		config := ParseConfig{TimeLayouts: []string{"02/01/2006", time.Kitchen}}
+
+		value, err := ParseWith[time.Time]("02/01/2024", config)
+		require.NoError(t, err)
+		require.Equal(t, Some(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), value)
+
+		value, err = ParseWith[time.Time]("3:04PM", config)
+		require.NoError(t, err)
+		require.Equal(t, 15, value.MustGet().Hour())
+
+		_, err = ParseWith[time.Time]("2024-01-02", config)
+		require.ErrorContains(t, err, `as "02/01/2006"`)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		value, err := Parse[int]("")
+		require.NoError(t, err)
+		require.Equal(t, None[int](), value)
+
+		_, err = ParseWith[string]("", ParseConfig{Empty: EmptyAsError})
+		require.ErrorIs(t, err, ErrEmpty)
+
+		str, err := ParseWith[string]("", ParseConfig{Empty: EmptyAsValue})
+		require.NoError(t, err)
+		require.Equal(t, Some(""), str)
+
+		_, err = ParseWith[int]("", ParseConfig{Empty: EmptyAsValue})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrEmpty)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Parse[int]("x")
+
+		var parseErr *ParseError
+
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, "int", parseErr.Type)
+		require.Equal(t, "x", parseErr.Input)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+		require.EqualError(t, err, `opt: parse "x" as int: strconv.ParseInt: parsing "x": invalid syntax`)
+
+		_, err = Parse[chan int]("x")
+		require.ErrorContains(t, err, "unsupported type chan int")
+	})
+
+	t.Run("register", func(t *testing.T) {
+		unregisterParser[parseCelsius](t)
+		unregisterParser[parseLabel](t)
+
+		RegisterParser(func(s string) (parseCelsius, error) {
+			s, ok := strings.CutSuffix(s, "C")
+			if !ok {
+				return 0, errors.New("missing unit")
+			}
+
+			f, err := strconv.ParseFloat(s, 64)
+
+			return parseCelsius(f), err
+		})
+
+		requireParse(t, "36.6C", Some(parseCelsius(36.6)))
+		requireParse(t, "36.6C", Some(Some(parseCelsius(36.6)).ToPtr()))
+
+		_, err := Parse[parseCelsius]("36.6")
+		require.ErrorContains(t, err, "missing unit")
+
+		var wg sync.WaitGroup
+
+		for range 4 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				RegisterParser(func(s string) (parseLabel, error) { return nil, nil })
+
+				value, err := Parse[parseLabel]("x")
+				assert.NoError(t, err)
+				assert.Equal(t, Some[parseLabel](nil), value)
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	// the registered parsers are removed by the cleanup
+	requireParse(t, "36.6", Some(parseCelsius(36.6)))
+}
+
+func requireParse[T any](t *testing.T, s string, want Opt[T]) {
+	t.Helper()
+
+	value, err := Parse[T](s)
+	require.NoError(t, err)
+	require.Equal(t, want, value)
+}
+
+type codeError struct {
+	Code int
+}
+
+func (e codeError) Error() string {
+	return fmt.Sprintf("code %d", e.Code)
+}
+
+// aliasError matches codeError via As method
+type aliasError struct{}
+
+func (aliasError) Error() string {
+	return "alias"
+}
+
+func (aliasError) As(target any) bool {
+	if target, ok := target.(*codeError); ok {
+		*target = codeError{Code: -1}
+
+		return true
+	}
+
+	return false
+}
+
+func TestFromErr(t *testing.T) {
+	require.Equal(t, Some(1), FromErr(1, nil))
+	require.Equal(t, None[int](), FromErr(1, errors.New("failed")))
+	require.Equal(t, Some(0), FromErr(strconv.Atoi("0")))
+	require.Equal(t, None[int](), FromErr(strconv.Atoi("x")))
+}
+
+func TestCast(t *testing.T) {
+	require.Equal(t, Some(1), Cast[int](1))
+	require.Equal(t, None[int](), Cast[int]("1"))
+	require.Equal(t, None[int](), Cast[int](nil))
+
+	require.Equal(t, Some[fmt.Stringer](time.Second), Cast[fmt.Stringer](time.Second))
+	require.Equal(t, None[fmt.Stringer](), Cast[fmt.Stringer](1))
+	require.Equal(t, None[error](), Cast[error](nil))
+}
+
+func TestAsError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", errors.Join(
+		errors.New("plain"),
+		fmt.Errorf("inner: %w", codeError{Code: 1}),
+		codeError{Code: 2},
+	))
+
+	require.Equal(t, Some(codeError{Code: 1}), AsError[codeError](err))
+	require.Equal(t, None[*ParseError](), AsError[*ParseError](err))
+	require.Equal(t, None[codeError](), AsError[codeError](nil))
+	require.Equal(t, Some(codeError{Code: -1}), AsError[codeError](aliasError{}))
+}
+
+func TestErrorsOfType(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", errors.Join(
+		errors.New("plain"),
+		fmt.Errorf("inner: %w", codeError{Code: 1}),
+		errors.Join(codeError{Code: 2}, aliasError{}),
+	))
+
+	require.Equal(t,
+		[]codeError{{Code: 1}, {Code: 2}, {Code: -1}},
+		ErrorsOfType[codeError](err),
+	)
+
+	require.Nil(t, ErrorsOfType[*ParseError](err))
+	require.Nil(t, ErrorsOfType[codeError](nil))
+	require.Len(t, ErrorsOfType[error](err), 8)
+}
+
+func TestEither(t *testing.T) {
+	left := Left[string, int]("failed")
+	right := Right[string](42)
+
+	require.True(t, left.IsLeft())
+	require.False(t, left.IsRight())
+	require.True(t, right.IsRight())
+	require.True(t, Either[string, int]{}.IsLeft())
+
+	require.Equal(t, Some("failed"), left.LeftOpt())
+	require.Equal(t, None[int](), left.RightOpt())
+	require.Equal(t, None[string](), right.LeftOpt())
+	require.Equal(t, Some(42), right.RightOpt())
+
+	require.Equal(t, Right[int]("failed"), left.Swap())
+	require.Equal(t, Left[int, string](42), right.Swap())
+
+	require.Equal(t, Left[int, int](6), MapLeft(left, func(s string) int { return len(s) }))
+	require.Equal(t, Right[int](42), MapLeft(right, func(s string) int { return len(s) }))
+	require.Equal(t, Left[string, string]("failed"), MapRight(left, strconv.Itoa))
+	require.Equal(t, Right[string]("42"), MapRight(right, strconv.Itoa))
+
+	describe := func(e Either[string, int]) string {
+		return Fold(e,
+			func(s string) string { return "error: " + s },
+			func(n int) string { return "value: " + strconv.Itoa(n) },
+		)
+	}
+
+	require.Equal(t, "error: failed", describe(left))
+	require.Equal(t, "value: 42", describe(right))
+
+	require.Equal(t, "Left(failed)", left.String())
+	require.Equal(t, "Right(42)", right.String())
+}
+
+func TestEither_JSON(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	type problem struct {
+		Code int `json:"code"`
+	}
+
+	t.Run("default", func(t *testing.T) {
+		data, err := json.Marshal(struct {
+			Result Either[string, int] `json:"result"`
+		}{Result: Right[string](42)})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"result":{"type":"right","value":42}}`, string(data))
+
+		var decoded Either[string, int]
+
+		require.NoError(t, json.Unmarshal([]byte(`{"value":"failed","type":"left"}`), &decoded))
+		require.Equal(t, Left[string, int]("failed"), decoded)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"type":"right","value":1}`), &decoded))
+		require.Equal(t, Right[string](1), decoded)
+
+		// zero tag is the default one
+		data, err = MarshalEither(Right[string](42), EitherTag{})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type":"right","value":42}`, string(data))
+
+		decoded, err = UnmarshalEither[string, int](data, EitherTag{})
+		require.NoError(t, err)
+		require.Equal(t, Right[string](42), decoded)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		tag := EitherTag{Key: "status", Left: "error", Right: "ok", Value: "data"}
+
+		data, err := MarshalEither(Left[problem, payload](problem{Code: 404}), tag)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"status":"error","data":{"code":404}}`, string(data))
+
+		decoded, err := UnmarshalEither[problem, payload](data, tag)
+		require.NoError(t, err)
+		require.Equal(t, Left[problem, payload](problem{Code: 404}), decoded)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		tag := EitherTag{Key: "kind"}
+
+		data, err := MarshalEither(Right[string](42), tag)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"kind":"right","value":42}`, string(data))
+
+		decoded, err := UnmarshalEither[string, int](data, tag)
+		require.NoError(t, err)
+		require.Equal(t, Right[string](42), decoded)
+
+		decoded, err = UnmarshalEither[string, int]([]byte(`{"kind":"left","value":"x"}`), tag)
+		require.NoError(t, err)
+		require.Equal(t, Left[string, int]("x"), decoded)
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		for _, tag := range []EitherTag{
+			{Left: "a", Right: "b"},
+			{Key: "kind", Left: "same", Right: "same"},
+			{Key: "kind", Right: "left"},
+			{Key: "value"},
+		} {
+			_, err := MarshalEither(Right[string](42), tag)
+			require.Error(t, err, "%+v", tag)
+
+			_, err = UnmarshalEither[string, int]([]byte(`{"kind":"right","value":42}`), tag)
+			require.Error(t, err, "%+v", tag)
+		}
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		tag := EitherTag{Key: "kind", Left: "problem", Right: "user", Inline: true}
+
+		data, err := MarshalEither(Right[problem](payload{Name: "bob"}), tag)
+		require.NoError(t, err)
+		require.Equal(t, `{"kind":"user","name":"bob"}`, string(data))
+
+		decoded, err := UnmarshalEither[problem, payload](data, tag)
+		require.NoError(t, err)
+		require.Equal(t, Right[problem](payload{Name: "bob"}), decoded)
+
+		data, err = MarshalEither(Right[problem](struct{}{}), tag)
+		require.NoError(t, err)
+		require.Equal(t, `{"kind":"user"}`, string(data))
+
+		_, err = MarshalEither(Right[problem](42), tag)
+		require.ErrorContains(t, err, "must be a JSON object")
+
+		_, err = MarshalEither(Right[problem](map[string]int{"kind": 1}), tag)
+		require.ErrorContains(t, err, "already contains discriminator")
+
+		// the discriminator is not decoded into map payloads
+		data, err = MarshalEither(Left[map[string]int, payload](map[string]int{"a": 1}), tag)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"kind":"problem","a":1}`, string(data))
+
+		decodedMap, err := UnmarshalEither[map[string]int, payload](data, tag)
+		require.NoError(t, err)
+		require.Equal(t, Left[map[string]int, payload](map[string]int{"a": 1}), decodedMap)
+
+		decodedAny, err := UnmarshalEither[map[string]any, payload](data, tag)
+		require.NoError(t, err)
+		require.Equal(t, Left[map[string]any, payload](map[string]any{"a": 1.0}), decodedAny)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			data  string
+			error string
+		}{
+			{name: "not object", data: `[]`, error: "cannot unmarshal array"},
+			{name: "null", data: `null`, error: "got null"},
+			{name: "missing discriminator", data: `{"value":1}`, error: `key "type" is missing`},
+			{name: "invalid discriminator", data: `{"type":1}`, error: "either discriminator"},
+			{name: "unknown discriminator", data: `{"type":"up","value":1}`, error: `unknown either discriminator "up"`},
+			{name: "missing value", data: `{"type":"left"}`, error: `key "value" is missing`},
+			{name: "invalid value", data: `{"type":"right","value":"x"}`, error: "cannot unmarshal string"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				var decoded Either[string, int]
+
+				require.ErrorContains(t, json.Unmarshal([]byte(tc.data), &decoded), tc.error)
+			})
+		}
+	})
+}
+
+func TestMatch(t *testing.T) {
+	describe := func(o Opt[int]) string {
+		return Match(o,
+			func(n int) string { return "some " + strconv.Itoa(n) },
+			func() string { return "none" },
+		)
+	}
+
+	require.Equal(t, "some 1", describe(Some(1)))
+	require.Equal(t, "none", describe(None[int]()))
+	require.Equal(t, "none", describe(Opt[int]{}))
+}
+
+func TestMatchExplicit(t *testing.T) {
+	describe := func(o Opt[int]) string {
+		return MatchExplicit(o,
+			func(n int) string { return "some " + strconv.Itoa(n) },
+			func() string { return "none" },
+			func() string { return "unset" },
+		)
+	}
+
+	require.Equal(t, "some 1", describe(Some(1)))
+	require.Equal(t, "none", describe(None[int]()))
+	require.Equal(t, "unset", describe(Opt[int]{}))
+}
+
+func TestMatchErr(t *testing.T) {
+	describe := func(s string) string {
+		value, err := Parse[int](s)
+
+		return MatchErr(value, err,
+			func(n int) string { return "some " + strconv.Itoa(n) },
+			func() string { return "none" },
+			func(err error) string { return "failed" },
+		)
+	}
+
+	require.Equal(t, "some 1", describe("1"))
+	require.Equal(t, "none", describe(""))
+	require.Equal(t, "failed", describe("x"))
+
+	// error takes precedence over the option
+	require.Equal(t, "failed", MatchErr(Some(1), errors.New("x"),
+		func(int) string { return "some" },
+		func() string { return "none" },
+		func(error) string { return "failed" },
+	))
+}
+
+func TestOpt_Switch(t *testing.T) {
+	var calls []string
+
+	some := func(n int) { calls = append(calls, "some "+strconv.Itoa(n)) }
+	none := func() { calls = append(calls, "none") }
+
+	Some(1).Switch(some, none)
+	None[int]().Switch(some, none)
+	Opt[int]{}.Switch(some, none)
+
+	require.Equal(t, []string{"some 1", "none", "none"}, calls)
+}
+
 func TestOpt_FromZero(t *testing.T) {
 	require.Equal(t, None[string](), FromZero(""))
 	require.Equal(t, Some("foo"), FromZero("foo"))
@@ -94,67 +983,156 @@ func TestOpt_IsExplicit(t *testing.T) {
 		Age  Opt[int]
 	}
 
-	JSONEncoder{}.Decode(t, []byte(`{"name":"bar","age":null}`), &foo)
+	decode(t, codecs.JSON, []byte(`{"name":"bar","age":null}`), &foo)
 	require.True(t, foo.Age.IsExplicit())
 
-	JSONEncoder{}.Decode(t, []byte(`{"name":"bar"}`), &bar)
+	decode(t, codecs.JSON, []byte(`{"name":"bar"}`), &bar)
 	require.False(t, bar.Age.IsExplicit())
 }
 
+func TestOpt_Generate(t *testing.T) {
+	type Nested struct {
+		A Opt[int]
+		B Opt[*string]
+	}
+
+	states := make(map[string]int)
+
+	err := quick.Check(func(o Opt[Nested]) bool {
+		switch {
+		case o.IsSome():
+			states["some"]++
+		case o.IsExplicit():
+			states["explicit"]++
+		default:
+			states["implicit"]++
+		}
+
+		// JSON does not preserve implicit None, so it is compared as a value
+		data, err := json.Marshal(o)
+		if err != nil {
+			return false
+		}
+
+		var decoded Opt[Nested]
+
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return false
+		}
+
+		return decoded.IsSome() == o.IsSome()
+	}, &quick.Config{MaxCount: 300})
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+
+	// Some of unsupported type is generated at least once
+	require.Panics(t, func() {
+		for i := 0; i < 100; i++ {
+			Opt[chan int]{}.Generate(rand.New(rand.NewSource(int64(i))), 10)
+		}
+	})
+}
+
+func TestOpt_CBOR(t *testing.T) {
+	for _, o := range []Opt[string]{Some("apple"), None[string](), {}} {
+		data, err := o.MarshalCBOR()
+		require.NoError(t, err)
+
+		var decoded Opt[string]
+
+		require.NoError(t, decoded.UnmarshalCBOR(data))
+		require.Equal(t, o, decoded)
+	}
+
+	data, err := Some("apple").MarshalCBOR()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x65, 0x61, 0x70, 0x70, 0x6C, 0x65}, data)
+}
+
 func TestEncode(t *testing.T) {
 	testCases := []struct {
 		name      string
 		wantOpt   Opt[string]
 		wantBytes []byte
-		encoder   Encoder
+		codec     codecs.Codec
 	}{
 		{
 			name:      "json some",
 			wantOpt:   Some("apple"),
 			wantBytes: []byte(`"apple"`),
-			encoder:   JSONEncoder{},
+			codec:     codecs.JSON,
 		},
 		{
 			name:      "json none",
 			wantOpt:   None[string](),
 			wantBytes: []byte(`null`),
-			encoder:   JSONEncoder{},
+			codec:     codecs.JSON,
 		},
 		{
 			name:      "text some",
 			wantOpt:   Some("apple"),
 			wantBytes: []byte(`"apple"`),
-			encoder:   TextEncoder{},
+			codec:     codecs.Text,
 		},
 		{
 			name:      "text none",
 			wantOpt:   None[string](),
 			wantBytes: []byte(`null`),
-			encoder:   TextEncoder{},
+			codec:     codecs.Text,
 		},
 		{
 			name:      "binary some",
 			wantOpt:   Some("apple"),
 			wantBytes: []byte{1, 0x8, 0xC, 0x0, 0x5, 0x61, 0x70, 0x70, 0x6C, 0x65},
-			encoder:   BinaryEncoder{},
+			codec:     codecs.Binary,
 		},
 		{
 			name:      "binary none",
 			wantOpt:   None[string](),
 			wantBytes: []byte{0},
-			encoder:   BinaryEncoder{},
+			codec:     codecs.Binary,
 		},
 		{
 			name:      "gob some",
 			wantOpt:   Some("apple"),
 			wantBytes: []byte{0x16, 0x7F, 0x5, 0x1, 0x1, 0xB, 0x4F, 0x70, 0x74, 0x5B, 0x73, 0x74, 0x72, 0x69, 0x6E, 0x67, 0x5D, 0x1, 0xFF, 0x80, 0x0, 0x0, 0x0, 0xE, 0xFF, 0x80, 0x0, 0xA, 0x1, 0x8, 0xC, 0x0, 0x5, 0x61, 0x70, 0x70, 0x6C, 0x65},
-			encoder:   GobEncoder{},
+			codec:     codecs.Gob,
 		},
 		{
 			name:      "gob none",
 			wantOpt:   None[string](),
 			wantBytes: []byte{0x16, 0x7F, 0x5, 0x1, 0x1, 0xB, 0x4F, 0x70, 0x74, 0x5B, 0x73, 0x74, 0x72, 0x69, 0x6E, 0x67, 0x5D, 0x1, 0xFF, 0x80, 0x0, 0x0, 0x0, 0x5, 0xFF, 0x80, 0x0, 0x1, 0x0},
-			encoder:   GobEncoder{},
+			codec:     codecs.Gob,
+		},
+		{
+			name:      "cbor some",
+			wantOpt:   Some("apple"),
+			wantBytes: []byte{0x65, 0x61, 0x70, 0x70, 0x6C, 0x65},
+			codec:     codecs.CBOR,
+		},
+		{
+			name:      "cbor none",
+			wantOpt:   None[string](),
+			wantBytes: []byte{0xF6},
+			codec:     codecs.CBOR,
+		},
+		{
+			name:      "cbor implicit none",
+			wantOpt:   Opt[string]{},
+			wantBytes: []byte{0xF7},
+			codec:     codecs.CBOR,
+		},
+		{
+			name:      "msgpack some",
+			wantOpt:   Some("apple"),
+			wantBytes: []byte{0xA5, 0x61, 0x70, 0x70, 0x6C, 0x65},
+			codec:     codecs.Msgpack,
+		},
+		{
+			name:      "msgpack none",
+			wantOpt:   None[string](),
+			wantBytes: []byte{0xC0},
+			codec:     codecs.Msgpack,
 		},
 	}
 
@@ -163,13 +1141,13 @@ func TestEncode(t *testing.T) {
 			t.Run("unmarshal", func(t *testing.T) {
 				var opt Opt[string]
 
-				tc.encoder.Decode(t, tc.wantBytes, &opt)
+				decode(t, tc.codec, tc.wantBytes, &opt)
 
 				require.Equal(t, tc.wantOpt, opt)
 			})
 
 			t.Run("marshal", func(t *testing.T) {
-				bytes := tc.encoder.Encode(t, tc.wantOpt)
+				bytes := encode(t, tc.codec, tc.wantOpt)
 
 				require.Equal(t, tc.wantBytes, bytes)
 			})
@@ -177,83 +1155,190 @@ func TestEncode(t *testing.T) {
 	}
 }
 
-type Encoder interface {
-	Encode(t *testing.T, v any) []byte
-	Decode(t *testing.T, data []byte, v any)
-}
-
-type JSONEncoder struct{}
-
-func (JSONEncoder) Encode(t *testing.T, v any) []byte {
+// encode encodes the value with the codec, failing the test on error.
+func encode(t *testing.T, c codecs.Codec, v any) []byte {
 	t.Helper()
 
-	b, err := json.Marshal(v)
+	b, err := c.Encode(v)
 	require.NoError(t, err)
 
-	return b
+	return b.([]byte)
 }
 
-func (JSONEncoder) Decode(t *testing.T, data []byte, v any) {
+// decode decodes the data with the codec, failing the test on error.
+func decode(t *testing.T, c codecs.Codec, data []byte, v any) {
 	t.Helper()
 
-	err := json.Unmarshal(data, v)
+	err := c.Decode(data, v)
 	require.NoError(t, err)
 }
 
-type TextEncoder struct{}
+func TestArray_Scan(t *testing.T) {
+	testCases := []struct {
+		name    string
+		literal string
+		want    Array[string]
+	}{
+		{
+			name:    "empty",
+			literal: `{}`,
+			want:    Array[string]{},
+		},
+		{
+			name:    "null elements",
+			literal: `{a,NULL,null,c}`,
+			want:    Array[string]{Some("a"), None[string](), None[string](), Some("c")},
+		},
+		{
+			name:    "quoted",
+			literal: `{"NULL","a b","c,d","e\"f","g\\h",""}`,
+			want:    Array[string]{Some("NULL"), Some("a b"), Some("c,d"), Some(`e"f`), Some(`g\h`), Some("")},
+		},
+		{
+			name:    "whitespace",
+			literal: `{ a , b }`,
+			want:    Array[string]{Some("a"), Some("b")},
+		},
+		{
+			name:    "escaped whitespace",
+			literal: `{a b ,a\ ,\ a,a\  ,n\ull}`,
+			want:    Array[string]{Some("a b"), Some("a "), Some(" a"), Some("a "), Some("null")},
+		},
+		{
+			name:    "dimension decoration",
+			literal: `[1:2]={a,b}`,
+			want:    Array[string]{Some("a"), Some("b")},
+		},
+	}
 
-func (TextEncoder) Encode(t *testing.T, v any) []byte {
-	t.Helper()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var array Array[string]
 
-	b, err := v.(encoding.TextMarshaler).MarshalText()
-	require.NoError(t, err)
+			err := array.Scan(tc.literal)
 
-	return b
-}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, array)
+		})
+	}
 
-func (TextEncoder) Decode(t *testing.T, data []byte, v any) {
-	t.Helper()
+	t.Run("null", func(t *testing.T) {
+		array := Array[string]{Some("a")}
 
-	err := v.(encoding.TextUnmarshaler).UnmarshalText(data)
-	require.NoError(t, err)
-}
+		err := array.Scan(nil)
 
-type BinaryEncoder struct{}
+		require.NoError(t, err)
+		require.Nil(t, array)
+	})
 
-func (BinaryEncoder) Encode(t *testing.T, v any) []byte {
-	t.Helper()
+	t.Run("ints", func(t *testing.T) {
+		var array Array[int]
 
-	b, err := v.(encoding.BinaryMarshaler).MarshalBinary()
-	require.NoError(t, err)
+		err := array.Scan([]byte(`{1,NULL,3}`))
 
-	return b
-}
+		require.NoError(t, err)
+		require.Equal(t, Array[int]{Some(1), None[int](), Some(3)}, array)
+	})
 
-func (BinaryEncoder) Decode(t *testing.T, data []byte, v any) {
-	t.Helper()
+	t.Run("nested", func(t *testing.T) {
+		var array Array[Array[int]]
 
-	err := v.(encoding.BinaryUnmarshaler).UnmarshalBinary(data)
-	require.NoError(t, err)
-}
+		err := array.Scan(`{{1,2},NULL,{NULL,4}}`)
 
-type GobEncoder struct{}
+		require.NoError(t, err)
+		require.Equal(t, Array[Array[int]]{
+			Some(Array[int]{Some(1), Some(2)}),
+			None[Array[int]](),
+			Some(Array[int]{None[int](), Some(4)}),
+		}, array)
+	})
 
-func (GobEncoder) Encode(t *testing.T, v any) []byte {
-	t.Helper()
+	t.Run("bytes", func(t *testing.T) {
+		var array Array[[]byte]
 
-	var buf bytes.Buffer
+		err := array.Scan(`{"\\x0102",NULL}`)
 
-	encoder := gob.NewEncoder(&buf)
-	err := encoder.Encode(v)
-	require.NoError(t, err)
+		require.NoError(t, err)
+		require.Equal(t, Array[[]byte]{Some([]byte{1, 2}), None[[]byte]()}, array)
+	})
+
+	t.Run("times", func(t *testing.T) {
+		var array Array[time.Time]
+
+		err := array.Scan(`{"2024-01-02 03:04:05+00","2024-01-02 03:04:05.123456+05:30",NULL,"2024-01-02 03:04:05",2024-01-02}`)
+
+		require.NoError(t, err)
+		require.Len(t, array, 5)
+		require.True(t, array[0].MustGet().Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+		require.True(t, array[1].MustGet().Equal(time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.FixedZone("", 5*3600+1800))))
+		require.Equal(t, None[time.Time](), array[2])
+		require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), array[3].MustGet())
+		require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), array[4].MustGet())
+
+		value, err := array[:1].Value()
+		require.NoError(t, err)
+
+		var roundtrip Array[time.Time]
+
+		require.NoError(t, roundtrip.Scan(value))
+		require.True(t, roundtrip[0].MustGet().Equal(array[0].MustGet()))
+
+		require.ErrorContains(t, roundtrip.Scan(`{yesterday}`), "malformed timestamp")
+	})
 
-	return buf.Bytes()
+	t.Run("malformed", func(t *testing.T) {
+		for _, literal := range []string{`{`, `{a`, `{"a}`, `{a,}`, `{a}b}`, `a,b`} {
+			var array Array[string]
+
+			require.Error(t, array.Scan(literal), literal)
+		}
+	})
 }
 
-func (GobEncoder) Decode(t *testing.T, data []byte, v any) {
-	t.Helper()
+func TestArray_Value(t *testing.T) {
+	testCases := []struct {
+		name  string
+		array driver.Valuer
+		want  driver.Value
+	}{
+		{
+			name:  "nil",
+			array: Array[string](nil),
+			want:  nil,
+		},
+		{
+			name:  "empty",
+			array: Array[string]{},
+			want:  `{}`,
+		},
+		{
+			name:  "strings",
+			array: Array[string]{Some("a"), None[string](), Some("NULL"), Some("a b"), Some(`c"\`), Some("")},
+			want:  `{a,NULL,"NULL","a b","c\"\\",""}`,
+		},
+		{
+			name:  "ints",
+			array: Array[int]{Some(1), None[int](), Some(3)},
+			want:  `{1,NULL,3}`,
+		},
+		{
+			name:  "nested",
+			array: Array[Array[int]]{Some(Array[int]{Some(1), None[int]()}), None[Array[int]]()},
+			want:  `{{1,NULL},NULL}`,
+		},
+		{
+			name:  "bytes",
+			array: Array[[]byte]{Some([]byte{1, 2})},
+			want:  `{"\\x0102"}`,
+		},
+	}
 
-	decoder := gob.NewDecoder(bytes.NewReader(data))
-	err := decoder.Decode(v)
-	require.NoError(t, err)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := tc.array.Value()
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, value)
+		})
+	}
 }
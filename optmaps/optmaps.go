@@ -0,0 +1,31 @@
+// Package optmaps provides map accessors returning [opt.Opt] instead of comma-ok results.
+package optmaps
+
+import (
+	"github.com/metafates/opt"
+)
+
+// Get returns the value at the given key or [opt.None] if the key does not exist, see [opt.IndexMap].
+func Get[M ~map[K]V, K comparable, V any](m M, key K) opt.Opt[V] {
+	return opt.IndexMap(m)(key)
+}
+
+// Insert sets the value at the given key and returns the old value
+// or [opt.None] if the key did not exist.
+func Insert[M ~map[K]V, K comparable, V any](m M, key K, value V) opt.Opt[V] {
+	old := Get(m, key)
+
+	m[key] = value
+
+	return old
+}
+
+// Delete deletes the value at the given key and returns it
+// or [opt.None] if the key did not exist.
+func Delete[M ~map[K]V, K comparable, V any](m M, key K) opt.Opt[V] {
+	old := Get(m, key)
+
+	delete(m, key)
+
+	return old
+}
@@ -0,0 +1,32 @@
+package optmaps
+
+import (
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	m := map[string]int{"zero": 0}
+
+	require.Equal(t, opt.Some(0), Get(m, "zero"))
+	require.Equal(t, opt.None[int](), Get(m, "one"))
+	require.Equal(t, opt.None[int](), Get(map[string]int(nil), "zero"))
+}
+
+func TestInsert(t *testing.T) {
+	m := map[string]int{"a": 1}
+
+	require.Equal(t, opt.Some(1), Insert(m, "a", 2))
+	require.Equal(t, opt.None[int](), Insert(m, "b", 3))
+	require.Equal(t, map[string]int{"a": 2, "b": 3}, m)
+}
+
+func TestDelete(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+
+	require.Equal(t, opt.Some(1), Delete(m, "a"))
+	require.Equal(t, opt.None[int](), Delete(m, "a"))
+	require.Equal(t, map[string]int{"b": 2}, m)
+}
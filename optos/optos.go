@@ -0,0 +1,40 @@
+// Package optos provides [os] functions returning [opt.Opt] for values that may not exist.
+package optos
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/metafates/opt"
+)
+
+// LookupEnv returns the value of the environment variable or [opt.None] if the variable is not present.
+//
+// Unlike [os.Getenv], variables set to an empty value are returned as Some("").
+func LookupEnv(key string) opt.Opt[string] {
+	return opt.FromTuple(os.LookupEnv(key))
+}
+
+// Stat returns the [fs.FileInfo] describing the named file or [opt.None] if the file does not exist.
+// Other errors, e.g. permission denied, are returned as is, see [os.Stat].
+func Stat(name string) (opt.Opt[fs.FileInfo], error) {
+	return fromStat(os.Stat(name))
+}
+
+// Lstat works like [Stat], but does not follow symbolic links, see [os.Lstat].
+func Lstat(name string) (opt.Opt[fs.FileInfo], error) {
+	return fromStat(os.Lstat(name))
+}
+
+func fromStat(info fs.FileInfo, err error) (opt.Opt[fs.FileInfo], error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		return opt.None[fs.FileInfo](), nil
+	}
+
+	if err != nil {
+		return opt.Opt[fs.FileInfo]{}, err
+	}
+
+	return opt.Some(info), nil
+}
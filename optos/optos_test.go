@@ -0,0 +1,49 @@
+package optos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupEnv(t *testing.T) {
+	t.Setenv("OPTOS_SET", "value")
+	t.Setenv("OPTOS_EMPTY", "")
+
+	require.Equal(t, opt.Some("value"), LookupEnv("OPTOS_SET"))
+	require.Equal(t, opt.Some(""), LookupEnv("OPTOS_EMPTY"))
+	require.Equal(t, opt.None[string](), LookupEnv("OPTOS_MISSING"))
+}
+
+func TestStat(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o600))
+
+	info, err := Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), info.MustGet().Size())
+
+	info, err = Stat(filepath.Join(dir, "missing.txt"))
+	require.NoError(t, err)
+	require.True(t, info.IsNone())
+
+	// not a directory is neither existence nor absence
+	_, err = Stat(filepath.Join(file, "child"))
+	require.Error(t, err)
+
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing.txt"), link))
+
+	info, err = Stat(link)
+	require.NoError(t, err)
+	require.True(t, info.IsNone())
+
+	info, err = Lstat(link)
+	require.NoError(t, err)
+	require.NotZero(t, info.MustGet().Mode()&os.ModeSymlink)
+}
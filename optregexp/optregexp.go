@@ -0,0 +1,67 @@
+// Package optregexp provides [regexp] functions returning [opt.Opt],
+// separating no match from an empty match.
+package optregexp
+
+import (
+	"regexp"
+
+	"github.com/metafates/opt"
+)
+
+// Find returns the leftmost match of re in s or [opt.None] if there is no match, see [regexp.Regexp.FindString].
+//
+// Unlike [regexp.Regexp.FindString], an empty match is returned as Some("").
+func Find(re *regexp.Regexp, s string) opt.Opt[string] {
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return opt.None[string]()
+	}
+
+	return opt.Some(s[loc[0]:loc[1]])
+}
+
+// FindSubmatch returns the leftmost match of re in s and its submatches, where submatches
+// which did not participate in the match are [opt.None], or [opt.None] if there is no match.
+func FindSubmatch(re *regexp.Regexp, s string) opt.Opt[[]opt.Opt[string]] {
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return opt.None[[]opt.Opt[string]]()
+	}
+
+	submatches := make([]opt.Opt[string], len(loc)/2)
+
+	for i := range submatches {
+		if start, end := loc[2*i], loc[2*i+1]; start >= 0 {
+			submatches[i] = opt.Some(s[start:end])
+		}
+	}
+
+	return opt.Some(submatches)
+}
+
+// Named returns the named groups of the leftmost match of re in s or [opt.None] if there is no match.
+//
+// Groups which did not participate in the match are omitted from the map,
+// so that [optmaps.Get] returns [opt.None] for them.
+//
+// [optmaps.Get]: https://pkg.go.dev/github.com/metafates/opt/optmaps#Get
+func Named(re *regexp.Regexp, s string) opt.Opt[map[string]string] {
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return opt.None[map[string]string]()
+	}
+
+	groups := make(map[string]string)
+
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		if start, end := loc[2*i], loc[2*i+1]; start >= 0 {
+			groups[name] = s[start:end]
+		}
+	}
+
+	return opt.Some(groups)
+}
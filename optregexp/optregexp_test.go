@@ -0,0 +1,49 @@
+package optregexp
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	re := regexp.MustCompile(`a*`)
+
+	require.Equal(t, opt.Some("aa"), Find(re, "aab"))
+	require.Equal(t, opt.Some(""), Find(re, "b"))
+	require.Equal(t, opt.None[string](), Find(regexp.MustCompile(`x`), "b"))
+}
+
+func TestFindSubmatch(t *testing.T) {
+	re := regexp.MustCompile(`(\w+)@(\w+)?`)
+
+	require.Equal(t,
+		opt.Some([]opt.Opt[string]{opt.Some("bob@host"), opt.Some("bob"), opt.Some("host")}),
+		FindSubmatch(re, "bob@host"),
+	)
+
+	require.Equal(t,
+		opt.Some([]opt.Opt[string]{opt.Some("bob@"), opt.Some("bob"), {}}),
+		FindSubmatch(re, "bob@"),
+	)
+
+	require.True(t, FindSubmatch(re, "bob").IsNone())
+}
+
+func TestNamed(t *testing.T) {
+	re := regexp.MustCompile(`(?P<key>\w+)=(?P<value>\w*)(?:;(?P<comment>.+))?`)
+
+	require.Equal(t,
+		opt.Some(map[string]string{"key": "name", "value": "bob", "comment": "user"}),
+		Named(re, "name=bob;user"),
+	)
+
+	require.Equal(t,
+		opt.Some(map[string]string{"key": "name", "value": ""}),
+		Named(re, "name="),
+	)
+
+	require.True(t, Named(re, "name").IsNone())
+}
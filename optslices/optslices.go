@@ -0,0 +1,79 @@
+// Package optslices provides [slices] functions returning [opt.Opt] instead of comma-ok, -1 and panicking results.
+package optslices
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/metafates/opt"
+)
+
+// At returns the element at the given index or [opt.None] if the index is out of range, see [opt.IndexSlice].
+func At[S ~[]E, E any](s S, i int) opt.Opt[E] {
+	return opt.IndexSlice(s)(i)
+}
+
+// Index returns the index of the first occurrence of v in s or [opt.None] if not present.
+func Index[S ~[]E, E comparable](s S, v E) opt.Opt[int] {
+	return fromIndex(slices.Index(s, v))
+}
+
+// IndexFunc returns the first index i satisfying f(s[i]) or [opt.None] if none do.
+func IndexFunc[S ~[]E, E any](s S, f func(E) bool) opt.Opt[int] {
+	return fromIndex(slices.IndexFunc(s, f))
+}
+
+// Max returns the maximal value in s or [opt.None] if s is empty, see [slices.Max].
+func Max[S ~[]E, E cmp.Ordered](s S) opt.Opt[E] {
+	if len(s) == 0 {
+		return opt.None[E]()
+	}
+
+	return opt.Some(slices.Max(s))
+}
+
+// MaxFunc returns the maximal value in s, using cmp to compare elements,
+// or [opt.None] if s is empty, see [slices.MaxFunc].
+func MaxFunc[S ~[]E, E any](s S, cmp func(a, b E) int) opt.Opt[E] {
+	if len(s) == 0 {
+		return opt.None[E]()
+	}
+
+	return opt.Some(slices.MaxFunc(s, cmp))
+}
+
+// Min returns the minimal value in s or [opt.None] if s is empty, see [slices.Min].
+func Min[S ~[]E, E cmp.Ordered](s S) opt.Opt[E] {
+	if len(s) == 0 {
+		return opt.None[E]()
+	}
+
+	return opt.Some(slices.Min(s))
+}
+
+// MinFunc returns the minimal value in s, using cmp to compare elements,
+// or [opt.None] if s is empty, see [slices.MinFunc].
+func MinFunc[S ~[]E, E any](s S, cmp func(a, b E) int) opt.Opt[E] {
+	if len(s) == 0 {
+		return opt.None[E]()
+	}
+
+	return opt.Some(slices.MinFunc(s, cmp))
+}
+
+// BinarySearch searches for target in a sorted slice and returns its index
+// or [opt.None] if not found, see [slices.BinarySearch].
+//
+// Use [slices.BinarySearch] directly if the insertion position is needed.
+func BinarySearch[S ~[]E, E cmp.Ordered](s S, target E) opt.Opt[int] {
+	return opt.FromTuple(slices.BinarySearch(s, target))
+}
+
+// BinarySearchFunc works like [BinarySearch], but uses a custom comparison function, see [slices.BinarySearchFunc].
+func BinarySearchFunc[S ~[]E, E, T any](s S, target T, cmp func(E, T) int) opt.Opt[int] {
+	return opt.FromTuple(slices.BinarySearchFunc(s, target, cmp))
+}
+
+func fromIndex(i int) opt.Opt[int] {
+	return opt.FromTuple(i, i >= 0)
+}
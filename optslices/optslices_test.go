@@ -0,0 +1,60 @@
+package optslices
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAt(t *testing.T) {
+	s := []string{"a", "b"}
+
+	require.Equal(t, opt.Some("b"), At(s, 1))
+	require.Equal(t, opt.None[string](), At(s, 2))
+	require.Equal(t, opt.None[string](), At(s, -1))
+}
+
+func TestIndex(t *testing.T) {
+	s := []int{0, 42, 8}
+
+	require.Equal(t, opt.Some(1), Index(s, 42))
+	require.Equal(t, opt.None[int](), Index(s, 7))
+
+	require.Equal(t, opt.Some(0), IndexFunc(s, func(n int) bool { return n%2 == 0 }))
+	require.Equal(t, opt.None[int](), IndexFunc(s, func(n int) bool { return n < 0 }))
+}
+
+func TestMax(t *testing.T) {
+	require.Equal(t, opt.Some(42), Max([]int{0, 42, -10, 8}))
+	require.Equal(t, opt.None[int](), Max([]int(nil)))
+
+	require.Equal(t, opt.Some(-10), Min([]int{0, 42, -10, 8}))
+	require.Equal(t, opt.None[int](), Min([]int{}))
+
+	byLen := func(a, b string) int { return cmp.Compare(len(a), len(b)) }
+
+	require.Equal(t, opt.Some("gopher"), MaxFunc([]string{"go", "gopher", "g"}, byLen))
+	require.Equal(t, opt.Some("g"), MinFunc([]string{"go", "gopher", "g"}, byLen))
+	require.Equal(t, opt.None[string](), MaxFunc([]string(nil), byLen))
+	require.Equal(t, opt.None[string](), MinFunc([]string(nil), byLen))
+}
+
+func TestBinarySearch(t *testing.T) {
+	s := []string{"Alice", "Bob", "Vera"}
+
+	require.Equal(t, opt.Some(2), BinarySearch(s, "Vera"))
+	require.Equal(t, opt.None[int](), BinarySearch(s, "Bill"))
+
+	type person struct {
+		Name string
+	}
+
+	people := []person{{"Alice"}, {"Bob"}, {"Vera"}}
+	byName := func(p person, name string) int { return strings.Compare(p.Name, name) }
+
+	require.Equal(t, opt.Some(1), BinarySearchFunc(people, "Bob", byName))
+	require.Equal(t, opt.None[int](), BinarySearchFunc(people, "Zed", byName))
+}
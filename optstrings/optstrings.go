@@ -0,0 +1,49 @@
+// Package optstrings provides [strings] functions returning [opt.Opt] instead of comma-ok and -1 results.
+package optstrings
+
+import (
+	"strings"
+
+	"github.com/metafates/opt"
+)
+
+// Cut slices s around the first instance of sep, see [strings.Cut].
+//
+// If sep does not appear in s, Cut returns s and [opt.None].
+func Cut(s, sep string) (before string, after opt.Opt[string]) {
+	before, a, found := strings.Cut(s, sep)
+
+	return before, opt.FromTuple(a, found)
+}
+
+// CutPrefix returns s without the provided leading prefix string or [opt.None]
+// if s doesn't start with prefix, see [strings.CutPrefix].
+func CutPrefix(s, prefix string) opt.Opt[string] {
+	return opt.FromTuple(strings.CutPrefix(s, prefix))
+}
+
+// CutSuffix returns s without the provided ending suffix string or [opt.None]
+// if s doesn't end with suffix, see [strings.CutSuffix].
+func CutSuffix(s, suffix string) opt.Opt[string] {
+	return opt.FromTuple(strings.CutSuffix(s, suffix))
+}
+
+// Index returns the index of the first instance of substr in s or [opt.None] if substr is not present in s.
+func Index(s, substr string) opt.Opt[int] {
+	return fromIndex(strings.Index(s, substr))
+}
+
+// LastIndex returns the index of the last instance of substr in s or [opt.None] if substr is not present in s.
+func LastIndex(s, substr string) opt.Opt[int] {
+	return fromIndex(strings.LastIndex(s, substr))
+}
+
+// IndexFunc returns the index of the first Unicode code point satisfying f(c)
+// or [opt.None] if none do.
+func IndexFunc(s string, f func(rune) bool) opt.Opt[int] {
+	return fromIndex(strings.IndexFunc(s, f))
+}
+
+func fromIndex(i int) opt.Opt[int] {
+	return opt.FromTuple(i, i >= 0)
+}
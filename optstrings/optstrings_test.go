@@ -0,0 +1,46 @@
+package optstrings
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCut(t *testing.T) {
+	before, after := Cut("key=value", "=")
+	require.Equal(t, "key", before)
+	require.Equal(t, opt.Some("value"), after)
+
+	before, after = Cut("key=", "=")
+	require.Equal(t, "key", before)
+	require.Equal(t, opt.Some(""), after)
+
+	before, after = Cut("key", "=")
+	require.Equal(t, "key", before)
+	require.Equal(t, opt.None[string](), after)
+}
+
+func TestCutPrefix(t *testing.T) {
+	require.Equal(t, opt.Some("123"), CutPrefix("id123", "id"))
+	require.Equal(t, opt.Some(""), CutPrefix("id", "id"))
+	require.Equal(t, opt.None[string](), CutPrefix("123", "id"))
+
+	require.Equal(t, opt.Some("file"), CutSuffix("file.go", ".go"))
+	require.Equal(t, opt.None[string](), CutSuffix("file.rs", ".go"))
+}
+
+func TestIndex(t *testing.T) {
+	require.Equal(t, opt.Some(0), Index("chicken", "chi"))
+	require.Equal(t, opt.Some(0), Index("chicken", ""))
+	require.Equal(t, opt.None[int](), Index("chicken", "dmr"))
+
+	require.Equal(t, opt.Some(3), LastIndex("go gopher", "go"))
+	require.Equal(t, opt.None[int](), LastIndex("go gopher", "rodent"))
+
+	isHan := func(r rune) bool { return unicode.Is(unicode.Han, r) }
+
+	require.Equal(t, opt.Some(7), IndexFunc("Hello, 世界", isHan))
+	require.Equal(t, opt.None[int](), IndexFunc("Hello, world", isHan))
+}
@@ -0,0 +1,257 @@
+package opttest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metafates/opt"
+	"github.com/metafates/opt/internal/codecs"
+)
+
+// State is a state of an option.
+type State int
+
+const (
+	StateSome State = iota
+	StateExplicitNone
+	StateImplicitNone
+)
+
+func (s State) String() string {
+	switch s {
+	case StateSome:
+		return "Some"
+	case StateExplicitNone:
+		return "explicit None"
+	case StateImplicitNone:
+		return "implicit None"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Report lists the option states preserved by the round-trip, keyed by codec name.
+type Report map[string][]State
+
+// Preserves reports whether the codec preserves the given state.
+func (r Report) Preserves(codec string, state State) bool {
+	for _, s := range r[codec] {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Codec names, see [Config].
+const (
+	JSON    = "json"
+	Text    = "text"
+	Binary  = "binary"
+	Gob     = "gob"
+	CBOR    = "cbor"
+	Msgpack = "msgpack"
+	SQL     = "sql"
+)
+
+// Config configures [CheckCodecsWith] and [FuzzCodecsWith].
+type Config struct {
+	// Codecs are the names of the checked codecs.
+	// Defaults to [JSON], [Text], [Binary], [Gob] and [SQL]; [CBOR] and [Msgpack] are opt-in.
+	Codecs []string
+}
+
+// codecs returns the configured codecs or an error for unknown names.
+func (c Config) codecs() ([]codecs.Codec, error) {
+	names := c.Codecs
+	if len(names) == 0 {
+		names = []string{JSON, Text, Binary, Gob, SQL}
+	}
+
+	result := make([]codecs.Codec, 0, len(names))
+
+	for _, name := range names {
+		codec, ok := codecs.ByName(name)
+		if !ok {
+			return nil, fmt.Errorf("opttest: unknown codec %q", name)
+		}
+
+		result = append(result, codec)
+	}
+
+	return result, nil
+}
+
+func states[T any](sample T) map[State]opt.Opt[T] {
+	return map[State]opt.Opt[T]{
+		StateSome:         opt.Some(sample),
+		StateExplicitNone: opt.None[T](),
+		StateImplicitNone: {},
+	}
+}
+
+// CheckCodecs round-trips every sample as Some, explicit None and implicit None
+// through JSON, text, binary, gob and SQL Scan/Value and returns the states preserved by each codec.
+// Use [CheckCodecsWith] to choose the codecs, e.g. to add CBOR and MessagePack.
+//
+// The check fails if a codec returns an error, if Some is not decoded as Some with an equal value
+// or if None is not decoded as None. Losing the explicitness of None is expected for some codecs,
+// e.g. JSON encodes both as null, so it is only reported and logged.
+//
+//	func TestUserID(t *testing.T) {
+//		report := opttest.CheckCodecs(t, UserID(1), UserID(42))
+//
+//		require.True(t, report.Preserves("json", opttest.StateExplicitNone))
+//	}
+func CheckCodecs[T any](t testing.TB, samples ...T) Report {
+	t.Helper()
+
+	return CheckCodecsWith(t, Config{}, samples...)
+}
+
+// CheckCodecsWith works like [CheckCodecs], but checks the codecs of the given config.
+//
+//	opttest.CheckCodecsWith(t, opttest.Config{Codecs: []string{opttest.JSON, opttest.CBOR}}, UserID(1))
+func CheckCodecsWith[T any](t testing.TB, config Config, samples ...T) Report {
+	t.Helper()
+
+	if len(samples) == 0 {
+		t.Errorf("opttest: CheckCodecs requires at least one sample")
+
+		return nil
+	}
+
+	checked, err := config.codecs()
+	if err != nil {
+		t.Errorf("%v", err)
+
+		return nil
+	}
+
+	report := make(Report, len(checked))
+
+	var summary strings.Builder
+
+	for _, c := range checked {
+		fmt.Fprintf(&summary, "\n%s:", c.Name)
+
+		for _, state := range []State{StateSome, StateExplicitNone, StateImplicitNone} {
+			preserved := true
+
+			for i, sample := range samples {
+				if !checkCodec(t, c, fmt.Sprintf("sample %d as %s", i, state), states(sample)[state]) {
+					preserved = false
+				}
+			}
+
+			if preserved {
+				report[c.Name] = append(report[c.Name], state)
+				fmt.Fprintf(&summary, " %s preserved;", state)
+			} else {
+				fmt.Fprintf(&summary, " %s lost;", state)
+			}
+		}
+	}
+
+	t.Logf("opttest: codecs of %T:%s", *new(T), summary.String())
+
+	return report
+}
+
+// checkCodec round-trips the option through the codec and reports whether it was preserved.
+func checkCodec[T any](t testing.TB, c codecs.Codec, name string, src opt.Opt[T]) bool {
+	t.Helper()
+
+	encoded, err := c.Encode(src)
+	if err != nil {
+		t.Errorf("opttest: %s: %s: encode: %v", c.Name, name, err)
+
+		return false
+	}
+
+	var dst opt.Opt[T]
+
+	if err := c.Decode(encoded, &dst); err != nil {
+		t.Errorf("opttest: %s: %s: decode: %v", c.Name, name, err)
+
+		return false
+	}
+
+	want, isSome := src.TryGet()
+	if !isSome {
+		if dst.IsSome() {
+			t.Errorf("opttest: %s: %s: decoded as %s", c.Name, name, describe(dst))
+
+			return false
+		}
+
+		return dst.IsExplicit() == src.IsExplicit()
+	}
+
+	if !dst.IsSome() {
+		t.Errorf("opttest: %s: %s: decoded as %s", c.Name, name, describe(dst))
+
+		return false
+	}
+
+	return assert.Equal(t, want, dst.MustGet(), "opttest: %s: %s", c.Name, name)
+}
+
+// FuzzCodecs fuzzes decoding of options from arbitrary data with every codec checked by [CheckCodecs].
+// Use [FuzzCodecsWith] to choose the codecs.
+// The corpus is seeded with the encodings of the samples in all states.
+//
+// Decoding must not panic and a successfully decoded Some must survive another round-trip.
+//
+//	func FuzzUserID(f *testing.F) {
+//		opttest.FuzzCodecs(f, UserID(1), UserID(42))
+//	}
+func FuzzCodecs[T any](f *testing.F, samples ...T) {
+	f.Helper()
+
+	FuzzCodecsWith(f, Config{}, samples...)
+}
+
+// FuzzCodecsWith works like [FuzzCodecs], but fuzzes the codecs of the given config.
+func FuzzCodecsWith[T any](f *testing.F, config Config, samples ...T) {
+	f.Helper()
+
+	checked, err := config.codecs()
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	for _, sample := range samples {
+		for _, src := range states(sample) {
+			for _, c := range checked {
+				encoded, err := c.Encode(src)
+				if err != nil {
+					continue
+				}
+
+				switch encoded := encoded.(type) {
+				case []byte:
+					f.Add(encoded)
+				case string:
+					f.Add([]byte(encoded))
+				}
+			}
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, c := range checked {
+			var decoded opt.Opt[T]
+
+			if err := c.Decode(data, &decoded); err != nil || !decoded.IsSome() {
+				continue
+			}
+
+			checkCodec(t, c, "decoded "+describe(decoded), decoded)
+		}
+	})
+}
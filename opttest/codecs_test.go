@@ -0,0 +1,149 @@
+package opttest
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Status is stored as a string in the database.
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusBlocked
+)
+
+var statuses = map[Status]string{StatusActive: "active", StatusBlocked: "blocked"}
+
+func (s Status) Value() (driver.Value, error) {
+	return statuses[s], nil
+}
+
+func (s *Status) Scan(src any) error {
+	var name string
+
+	switch src := src.(type) {
+	case string:
+		name = src
+	case []byte:
+		name = string(src)
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+
+	for status, statusName := range statuses {
+		if statusName == name {
+			*s = status
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown status %q", name)
+}
+
+// Lossy loses its value when encoded as text.
+type Lossy struct {
+	Value string
+}
+
+func (Lossy) MarshalJSON() ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+// Broken fails to encode.
+type Broken struct{}
+
+func (Broken) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("broken")
+}
+
+func TestCheckCodecs(t *testing.T) {
+	all := []State{StateSome, StateExplicitNone}
+
+	t.Run("string", func(t *testing.T) {
+		report := CheckCodecs(t, "", "apple", "null")
+
+		require.Equal(t, Report{JSON: all, Text: all, Binary: all, Gob: all, SQL: all}, report)
+	})
+
+	t.Run("text marshaler", func(t *testing.T) {
+		report := CheckCodecs(t, netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::1"), netip.Addr{})
+
+		require.Equal(t, Report{JSON: all, Text: all, Binary: all, Gob: all, SQL: all}, report)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		report := CheckCodecsWith(t, Config{Codecs: []string{CBOR, Msgpack}}, netip.MustParseAddr("1.2.3.4"))
+
+		require.Equal(t, Report{CBOR: {StateSome, StateExplicitNone, StateImplicitNone}, Msgpack: all}, report)
+
+		r := &recorder{TB: t}
+
+		require.Nil(t, CheckCodecsWith(r, Config{Codecs: []string{"yaml"}}, 1))
+		require.Equal(t, []string{`opttest: unknown codec "yaml"`}, r.errors)
+	})
+
+	t.Run("scanner", func(t *testing.T) {
+		report := CheckCodecs(t, StatusActive, StatusBlocked)
+
+		require.True(t, report.Preserves("sql", StateSome))
+		require.False(t, report.Preserves("sql", StateImplicitNone))
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		type Point struct {
+			X, Y int
+		}
+
+		CheckCodecs(t, Point{X: 1, Y: 2})
+	})
+
+	t.Run("time", func(t *testing.T) {
+		CheckCodecs(t, time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC))
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		r := &recorder{TB: t}
+
+		report := CheckCodecs(r, Lossy{Value: "a"})
+
+		require.False(t, report.Preserves("json", StateSome))
+		require.True(t, report.Preserves("json", StateExplicitNone))
+		require.True(t, report.Preserves("gob", StateSome))
+		require.NotEmpty(t, r.errors)
+
+		r = &recorder{TB: t}
+
+		CheckCodecs(r, Broken{})
+		require.True(t, strings.Contains(strings.Join(r.errors, "\n"), "encode: json: error calling MarshalJSON"))
+
+		r = &recorder{TB: t}
+
+		require.Nil(t, CheckCodecs[int](r))
+		require.Len(t, r.errors, 1)
+	})
+}
+
+func FuzzCodecs_String(f *testing.F) {
+	FuzzCodecs(f, "", "apple")
+}
+
+func FuzzCodecs_Int(f *testing.F) {
+	FuzzCodecs(f, 0, -1, 42)
+}
+
+func FuzzCodecs_Status(f *testing.F) {
+	FuzzCodecs(f, StatusActive, StatusBlocked)
+}
+
+func FuzzCodecs_Addr(f *testing.F) {
+	FuzzCodecsWith(f, Config{Codecs: []string{JSON, Text, CBOR, Msgpack}}, netip.MustParseAddr("1.2.3.4"))
+}
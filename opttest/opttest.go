@@ -0,0 +1,87 @@
+// Package opttest provides test assertions for [opt.Opt] values.
+//
+// Assertions report failures with [testing.TB.Errorf] and return a boolean stating if they passed,
+// so the test continues after the failure. Use the returned value to stop early:
+//
+//	if !opttest.AssertSome(t, o, 5) {
+//		t.FailNow()
+//	}
+//
+// Options also implement [quick.Generator], see [opt.Opt.Generate].
+//
+// [quick.Generator]: https://pkg.go.dev/testing/quick#Generator
+package opttest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metafates/opt"
+)
+
+// AssertSome asserts that the option is Some with a value equal to want.
+// Values are compared with [assert.ObjectsAreEqual] and the diff is reported if they are not.
+func AssertSome[T any](t testing.TB, o opt.Opt[T], want T, msgAndArgs ...any) bool {
+	t.Helper()
+
+	value, ok := o.TryGet()
+	if !ok {
+		return fail(t, fmt.Sprintf("Expected Some(%#v), got %s", want, describe(o)), msgAndArgs...)
+	}
+
+	return assert.Equal(t, want, value, msgAndArgs...)
+}
+
+// AssertNone asserts that the option is None, either explicit or implicit.
+func AssertNone[T any](t testing.TB, o opt.Opt[T], msgAndArgs ...any) bool {
+	t.Helper()
+
+	if o.IsSome() {
+		return fail(t, "Expected None, got "+describe(o), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertExplicit asserts that the option was explicitly set, i.e. it is Some or explicit None.
+func AssertExplicit[T any](t testing.TB, o opt.Opt[T], msgAndArgs ...any) bool {
+	t.Helper()
+
+	if !o.IsExplicit() {
+		return fail(t, "Expected explicit option, got "+describe(o), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertImplicit asserts that the option is implicit None, e.g. a zero value.
+func AssertImplicit[T any](t testing.TB, o opt.Opt[T], msgAndArgs ...any) bool {
+	t.Helper()
+
+	if o.IsExplicit() {
+		return fail(t, "Expected implicit None, got "+describe(o), msgAndArgs...)
+	}
+
+	return true
+}
+
+// describe returns the state of the option, distinguishing explicit and implicit None.
+func describe[T any](o opt.Opt[T]) string {
+	if value, ok := o.TryGet(); ok {
+		return fmt.Sprintf("Some(%#v)", value)
+	}
+
+	if o.IsExplicit() {
+		return "explicit None"
+	}
+
+	return "implicit None"
+}
+
+func fail(t testing.TB, message string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	return assert.Fail(t, message, msgAndArgs...)
+}
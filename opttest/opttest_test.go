@@ -0,0 +1,120 @@
+package opttest
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metafates/opt"
+)
+
+// recorder records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	testCases := []struct {
+		name   string
+		assert func(t testing.TB) bool
+		want   string
+	}{
+		{
+			name:   "some",
+			assert: func(t testing.TB) bool { return AssertSome(t, opt.Some(5), 5) },
+		},
+		{
+			name:   "some with different value",
+			assert: func(t testing.TB) bool { return AssertSome(t, opt.Some([]int{1, 2}), []int{1, 3}) },
+			want:   "Diff:",
+		},
+		{
+			name:   "some of explicit none",
+			assert: func(t testing.TB) bool { return AssertSome(t, opt.None[int](), 5) },
+			want:   "Expected Some(5), got explicit None",
+		},
+		{
+			name:   "some of implicit none",
+			assert: func(t testing.TB) bool { return AssertSome(t, opt.Opt[string]{}, "a", "field %s", "name") },
+			want:   "field name",
+		},
+		{
+			name:   "none",
+			assert: func(t testing.TB) bool { return AssertNone(t, opt.Opt[int]{}) && AssertNone(t, opt.None[int]()) },
+		},
+		{
+			name:   "none of some",
+			assert: func(t testing.TB) bool { return AssertNone(t, opt.Some("a")) },
+			want:   `Expected None, got Some("a")`,
+		},
+		{
+			name:   "explicit",
+			assert: func(t testing.TB) bool { return AssertExplicit(t, opt.Some(1)) && AssertExplicit(t, opt.None[int]()) },
+		},
+		{
+			name:   "explicit of implicit",
+			assert: func(t testing.TB) bool { return AssertExplicit(t, opt.Opt[int]{}) },
+			want:   "Expected explicit option, got implicit None",
+		},
+		{
+			name:   "implicit",
+			assert: func(t testing.TB) bool { return AssertImplicit(t, opt.Opt[int]{}) },
+		},
+		{
+			name:   "implicit of explicit",
+			assert: func(t testing.TB) bool { return AssertImplicit(t, opt.None[int]()) },
+			want:   "Expected implicit None, got explicit None",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+
+			ok := tc.assert(r)
+
+			if tc.want == "" {
+				require.True(t, ok)
+				require.Empty(t, r.errors)
+
+				return
+			}
+
+			require.False(t, ok)
+			require.Len(t, r.errors, 1)
+			require.Contains(t, r.errors[0], tc.want)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var implicit, explicit, some int
+
+	err := quick.Check(func(o opt.Opt[[]int]) bool {
+		switch {
+		case o.IsSome():
+			some++
+		case o.IsExplicit():
+			explicit++
+		default:
+			implicit++
+		}
+
+		return AssertNone(t, o.Filter(func([]int) bool { return false }))
+	}, &quick.Config{MaxCount: 300})
+	require.NoError(t, err)
+
+	require.Positive(t, implicit)
+	require.Positive(t, explicit)
+	require.Positive(t, some)
+}
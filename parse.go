@@ -0,0 +1,150 @@
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/metafates/opt/internal/textconv"
+)
+
+// ErrEmpty is returned by [ParseWith] for empty strings with [EmptyAsError] policy.
+var ErrEmpty = errors.New("empty string")
+
+// EmptyPolicy defines how empty strings are parsed, see [ParseConfig].
+type EmptyPolicy int
+
+const (
+	// EmptyAsNone parses empty strings into explicit [None].
+	EmptyAsNone EmptyPolicy = iota
+
+	// EmptyAsError returns [ErrEmpty] for empty strings.
+	EmptyAsError
+
+	// EmptyAsValue parses empty strings as usual,
+	// e.g. into Some("") for strings or an error for numbers.
+	EmptyAsValue
+)
+
+// ParseConfig configures [ParseWith].
+type ParseConfig struct {
+	// Empty is the policy for empty strings.
+	Empty EmptyPolicy
+
+	// TimeLayouts are tried in order when parsing [time.Time].
+	// Defaults to [time.RFC3339], [time.DateTime] and [time.DateOnly].
+	TimeLayouts []string
+}
+
+// ParseError is an error of parsing a string, see [Parse].
+type ParseError struct {
+	// Type is the name of the type being parsed
+	Type string
+
+	// Input is the string being parsed
+	Input string
+
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("opt: parse %q as %s: %v", e.Input, e.Type, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// RegisterParser registers the parse function for the type T used by [Parse] and [ParseWith],
+// as well as by [ApplyDefaults] and the form, csvopt and httpopt packages.
+// Registered parsers take precedence over the built-in ones, replacing the previously registered parser for T.
+//
+// It is safe to call RegisterParser concurrently, although it is usually called from init functions.
+func RegisterParser[T any](parse func(s string) (T, error)) {
+	textconv.Register(reflect.TypeFor[T](), func(dst reflect.Value, s string) error {
+		value, err := parse(s)
+		if err != nil {
+			return err
+		}
+
+		dst.Set(reflect.ValueOf(&value).Elem())
+
+		return nil
+	})
+}
+
+// Parse parses the string into an option using the default [ParseConfig],
+// so that empty strings are parsed into explicit [None].
+//
+// Supported types are:
+//   - Types registered with [RegisterParser]
+//   - [time.Time], parsed with [ParseConfig.TimeLayouts]
+//   - Strings, booleans, integers and floats of all widths, parsed with [strconv], e.g. "1" and "t" are true
+//   - [time.Duration], parsed with [time.ParseDuration]
+//   - [url.URL], parsed with [url.Parse]
+//   - Types implementing [encoding.TextUnmarshaler], e.g. [netip.Addr]
+//   - Pointers to the types above
+//
+// Values are parsed in the same way as struct tag defaults by [ApplyDefaults]
+// and values decoded by the form, csvopt and httpopt packages,
+// which use the default time layouts.
+//
+// Parse failures are returned as [*ParseError].
+func Parse[T any](s string) (Opt[T], error) {
+	return ParseWith[T](s, ParseConfig{})
+}
+
+// ParseWith works like [Parse], but uses the given config.
+func ParseWith[T any](s string, config ParseConfig) (Opt[T], error) {
+	if s == "" {
+		switch config.Empty {
+		case EmptyAsNone:
+			return None[T](), nil
+		case EmptyAsError:
+			return Opt[T]{}, &ParseError{Type: typeName[T](), Input: s, Err: ErrEmpty}
+		}
+	}
+
+	var value T
+
+	if err := parseValue(reflect.ValueOf(&value).Elem(), s, config); err != nil {
+		return Opt[T]{}, &ParseError{Type: typeName[T](), Input: s, Err: err}
+	}
+
+	return Some(value), nil
+}
+
+func parseValue(dst reflect.Value, s string, config ParseConfig) error {
+	if _, ok := textconv.Registered(dst.Type()); ok {
+		return textconv.Parse(dst, s)
+	}
+
+	if dst.Type() == timeType && len(config.TimeLayouts) > 0 {
+		t, err := textconv.ParseTime(s, config.TimeLayouts)
+		if err != nil {
+			return err
+		}
+
+		dst.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		value := reflect.New(dst.Type().Elem())
+
+		if err := parseValue(value.Elem(), s, config); err != nil {
+			return err
+		}
+
+		dst.Set(value)
+
+		return nil
+	}
+
+	if !textconv.CanParse(dst.Type()) {
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+
+	return textconv.Parse(dst, s)
+}
@@ -0,0 +1,116 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PathError describes the step of the path which produced [None], see [Trace.Err].
+type PathError struct {
+	// Step is the 1-based index of the step which produced None.
+	// Zero means that the initial option was None.
+	Step int
+
+	// From and To are the package-qualified names of the input and output types of the step, e.g. main.User
+	From, To string
+}
+
+func (e *PathError) Error() string {
+	if e.Step == 0 {
+		return fmt.Sprintf("opt: path is None at the start (%s)", e.To)
+	}
+
+	return fmt.Sprintf("opt: path is None at step %d (%s -> %s)", e.Step, e.From, e.To)
+}
+
+// Trace is the result of the path navigation, see [Chain].
+// It embeds the final option and records the step which produced [None].
+type Trace[T any] struct {
+	Opt[T]
+
+	steps int
+	err   *PathError
+}
+
+// Err returns [*PathError] describing the step which produced [None] or nil if the trace is [Some].
+func (t Trace[T]) Err() error {
+	if t.err == nil {
+		return nil
+	}
+
+	return t.err
+}
+
+// Chain starts the nil-safe navigation from the given option.
+// Use [Then] to add steps or the typed [Path2], [Path3] and [Path4] helpers.
+//
+// Steps are functions returning options, so pointers, maps and slices are traversed
+// with [FromPtr], [IndexMap] and [IndexSlice]:
+//
+//	city := opt.Then(opt.Then(opt.Chain(opt.FromPtr(user)),
+//		func(u User) opt.Opt[Address] { return opt.FromPtr(u.Address) }),
+//		func(a Address) opt.Opt[string] { return opt.IndexMap(a.Lines)("city") })
+//
+//	if err := city.Err(); err != nil {
+//		log.Println(err) // opt: path is None at step 1 (main.User -> main.Address)
+//	}
+func Chain[T any](o Opt[T]) Trace[T] {
+	if !o.hasValue {
+		return Trace[T]{Opt: None[T](), err: &PathError{To: typeName[T]()}}
+	}
+
+	return Trace[T]{Opt: o}
+}
+
+// Then returns the trace of calling f with the contained value if the trace is [Some].
+// Otherwise, the trace is returned as [None] with the original step recorded.
+// The zero trace is None at the start, as if it was returned by [Chain].
+//
+// This is a function rather than a method, as it is limited by the lack of method type parameters in Go.
+func Then[T, U any](t Trace[T], f func(T) Opt[U]) Trace[U] {
+	if !t.hasValue {
+		err := t.err
+		if err == nil {
+			err = &PathError{To: typeName[T]()}
+		}
+
+		return Trace[U]{Opt: None[U](), steps: t.steps + 1, err: err}
+	}
+
+	next := f(t.value)
+
+	if !next.hasValue {
+		return Trace[U]{
+			Opt:   None[U](),
+			steps: t.steps + 1,
+			err:   &PathError{Step: t.steps + 1, From: typeName[T](), To: typeName[U]()},
+		}
+	}
+
+	return Trace[U]{Opt: next, steps: t.steps + 1}
+}
+
+// Path2 navigates from the option through two steps, see [Chain].
+func Path2[A, B, C any](a Opt[A], ab func(A) Opt[B], bc func(B) Opt[C]) Trace[C] {
+	return Then(Then(Chain(a), ab), bc)
+}
+
+// Path3 navigates from the option through three steps, see [Chain].
+func Path3[A, B, C, D any](a Opt[A], ab func(A) Opt[B], bc func(B) Opt[C], cd func(C) Opt[D]) Trace[D] {
+	return Then(Path2(a, ab, bc), cd)
+}
+
+// Path4 navigates from the option through four steps, see [Chain].
+func Path4[A, B, C, D, E any](
+	a Opt[A],
+	ab func(A) Opt[B],
+	bc func(B) Opt[C],
+	cd func(C) Opt[D],
+	de func(D) Opt[E],
+) Trace[E] {
+	return Then(Path3(a, ab, bc, cd), de)
+}
+
+func typeName[T any]() string {
+	return reflect.TypeFor[T]().String()
+}
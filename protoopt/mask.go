@@ -0,0 +1,215 @@
+package protoopt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+// MaskOf returns a field mask of the message listing the explicitly set fields of the patch struct (or a pointer to it).
+// Only the descriptor of msg is used, so a typed nil, e.g. (*pb.User)(nil), can be passed.
+//
+// Struct fields are matched with proto fields in the same way as [FromMessage] does and
+// paths are made of the matched proto field names.
+// Nested structs are listed as dotted paths, e.g. address.city. Some of a nested struct
+// without explicit fields is listed as a whole, as well as an explicit None.
+// Fields which are not options are always listed.
+func MaskOf(patch any, msg proto.Message) (*fieldmaskpb.FieldMask, error) {
+	rv := reflect.ValueOf(patch)
+
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("protoopt: expected struct patch, got %T", patch)
+	}
+
+	paths, err := maskPaths(nil, "", rv, msg.ProtoReflect().Descriptor())
+	if err != nil {
+		return nil, err
+	}
+
+	return &fieldmaskpb.FieldMask{Paths: paths}, nil
+}
+
+func maskPaths(paths []string, prefix string, v reflect.Value, md protoreflect.MessageDescriptor) ([]string, error) {
+	fields, err := mapFields(v.Type(), md)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		path := prefix + string(f.fd.Name())
+		value := v.FieldByIndex(f.index)
+
+		// nested structs are only listed by their fields for singular message fields
+		nestedMessage := f.fd.Message()
+		if f.fd.IsList() || f.fd.IsMap() {
+			nestedMessage = nil
+		}
+
+		if optreflect.Is(value.Type()) {
+			if !optreflect.IsExplicit(value) {
+				continue
+			}
+
+			if value, ok := optreflect.Get(value); ok && nestedMessage != nil {
+				if nested, ok := nestedStruct(value); ok {
+					nestedPaths, err := maskPaths(nil, path+".", nested, nestedMessage)
+					if err != nil {
+						return nil, err
+					}
+
+					if len(nestedPaths) > 0 {
+						paths = append(paths, nestedPaths...)
+
+						continue
+					}
+				}
+			}
+
+			paths = append(paths, path)
+
+			continue
+		}
+
+		if nested, ok := nestedStruct(value); ok && nestedMessage != nil {
+			if paths, err = maskPaths(paths, path+".", nested, nestedMessage); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// nestedStruct returns the struct value which is mapped to a nested message, dereferencing non-nil pointers.
+func nestedStruct(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer && !v.IsNil() && !v.Type().Implements(messageType) {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || v.Type() == timeType || optreflect.Is(v.Type()) {
+		return reflect.Value{}, false
+	}
+
+	return v, true
+}
+
+// ApplyMask returns a patch struct with only the masked fields of src explicit.
+//
+// The masked fields are set in the same way as [FromMessage] does, other options are left implicit.
+// Dotted paths, e.g. address.city, are applied to the nested structs, which are set to Some if they are options.
+func ApplyMask[P any](mask *fieldmaskpb.FieldMask, src proto.Message) (P, error) {
+	var patch P
+
+	rv := reflect.ValueOf(&patch).Elem()
+	if rv.Kind() != reflect.Struct {
+		return patch, fmt.Errorf("protoopt: expected struct patch, got %T", patch)
+	}
+
+	msg := src.ProtoReflect()
+
+	for _, path := range mask.GetPaths() {
+		if err := applyPath(rv, msg, strings.Split(path, ".")); err != nil {
+			return patch, fmt.Errorf("protoopt: path %q: %w", path, err)
+		}
+	}
+
+	return patch, nil
+}
+
+func applyPath(dst reflect.Value, msg protoreflect.Message, path []string) error {
+	fields, err := mapFields(dst.Type(), msg.Descriptor())
+	if err != nil {
+		return err
+	}
+
+	var f *field
+
+	for i := range fields {
+		if string(fields[i].fd.Name()) == path[0] {
+			f = &fields[i]
+
+			break
+		}
+	}
+
+	if f == nil {
+		if msg.Descriptor().Fields().ByName(protoreflect.Name(path[0])) == nil {
+			return fmt.Errorf("no field %s in %s", path[0], msg.Descriptor().FullName())
+		}
+
+		return fmt.Errorf("no struct field for %s", path[0])
+	}
+
+	value := dst.FieldByIndex(f.index)
+
+	if len(path) == 1 {
+		if err := fromField(value, msg, f.fd); err != nil {
+			return &FieldError{Field: f.name, Proto: f.fd.FullName(), Err: err}
+		}
+
+		return nil
+	}
+
+	if f.fd.Message() == nil || f.fd.IsList() || f.fd.IsMap() {
+		return &FieldError{Field: f.name, Proto: f.fd.FullName(), Err: errors.New("not a message field")}
+	}
+
+	nested := msg.Get(f.fd).Message()
+
+	switch {
+	case optreflect.Is(value.Type()):
+		target := reflect.New(optreflect.Elem(value.Type())).Elem()
+
+		// keep the fields set by the previous paths
+		if current, ok := optreflect.Get(value); ok {
+			target.Set(current)
+		}
+
+		if target.Kind() == reflect.Pointer && target.IsNil() && target.Type().Elem().Kind() == reflect.Struct {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		inner, ok := nestedStruct(target)
+		if !ok {
+			return &FieldError{Field: f.name, Proto: f.fd.FullName(), Err: fmt.Errorf("cannot apply nested path to %s", target.Type())}
+		}
+
+		if err := applyPath(inner, nested, path[1:]); err != nil {
+			return err
+		}
+
+		optreflect.SetSome(value, target)
+	default:
+		if value.Kind() == reflect.Pointer && value.IsNil() && value.Type().Elem().Kind() == reflect.Struct {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+
+		inner, ok := nestedStruct(value)
+		if !ok {
+			return &FieldError{Field: f.name, Proto: f.fd.FullName(), Err: fmt.Errorf("cannot apply nested path to %s", value.Type())}
+		}
+
+		return applyPath(inner, nested, path[1:])
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package protoopt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/metafates/opt"
+)
+
+type UserPatch struct {
+	Name      opt.Opt[string]
+	Age       opt.Opt[int]
+	Nick      opt.Opt[string] `proto:"nickname"`
+	Address   opt.Opt[Address]
+	CreatedAt opt.Opt[time.Time]
+	Tags      []string
+	Internal  string `proto:"-"`
+}
+
+func TestMaskOf(t *testing.T) {
+	testCases := []struct {
+		name  string
+		patch any
+		want  []string
+	}{
+		{
+			name:  "empty",
+			patch: UserPatch{},
+			want:  []string{"tags"},
+		},
+		{
+			name: "explicit",
+			patch: &UserPatch{
+				Name:      opt.Some("bob"),
+				Nick:      opt.None[string](),
+				CreatedAt: opt.Some(time.Now()),
+				Tags:      []string{"a"},
+			},
+			want: []string{"name", "nickname", "created_at", "tags"},
+		},
+		{
+			name: "nested",
+			patch: UserPatch{
+				Address: opt.Some(Address{Zip: opt.None[string]()}),
+			},
+			want: []string{"address.city", "address.zip", "tags"},
+		},
+		{
+			name: "nested without explicit fields",
+			patch: struct {
+				Address opt.Opt[struct{ Zip opt.Opt[string] }]
+			}{
+				Address: opt.Some(struct{ Zip opt.Opt[string] }{}),
+			},
+			want: []string{"address"},
+		},
+		{
+			name: "plain nested struct",
+			patch: struct {
+				Address *struct{ City opt.Opt[string] }
+				Age     opt.Opt[int]
+			}{
+				Address: &struct{ City opt.Opt[string] }{City: opt.Some("Paris")},
+				Age:     opt.Some(1),
+			},
+			want: []string{"address.city", "age"},
+		},
+		{
+			name: "descriptor names",
+			patch: struct {
+				Createdat opt.Opt[time.Time]
+				Base
+			}{
+				Createdat: opt.Some(time.Now()),
+				Base:      Base{Nickname: opt.Some("bob")},
+			},
+			want: []string{"created_at", "nickname"},
+		},
+	}
+
+	md := userDescriptor(t)
+	msg := dynamicpb.NewMessage(md)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mask, err := MaskOf(tc.patch, msg)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, mask.GetPaths())
+		})
+	}
+
+	_, err := MaskOf(1, msg)
+	require.Error(t, err)
+
+	var fieldErr *FieldError
+	_, err = MaskOf(struct{ Missing opt.Opt[int] }{}, msg)
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "Missing", fieldErr.Field)
+}
+
+// Base is embedded into patches to test flattening.
+type Base struct {
+	Nickname opt.Opt[string]
+}
+
+func TestApplyMask(t *testing.T) {
+	md := userDescriptor(t)
+
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("name"), protoreflect.ValueOfString("bob"))
+	msg.Set(md.Fields().ByName("age"), protoreflect.ValueOfInt32(30))
+
+	address := msg.Mutable(md.Fields().ByName("address")).Message()
+	address.Set(address.Descriptor().Fields().ByName("city"), protoreflect.ValueOfString("Paris"))
+
+	patch, err := ApplyMask[UserPatch](&fieldmaskpb.FieldMask{
+		Paths: []string{"name", "nickname", "address.city", "address.zip"},
+	}, msg)
+	require.NoError(t, err)
+
+	require.Equal(t, UserPatch{
+		Name:    opt.Some("bob"),
+		Nick:    opt.None[string](),
+		Address: opt.Some(Address{City: "Paris", Zip: opt.None[string]()}),
+	}, patch)
+
+	mask, err := MaskOf(patch, msg)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "nickname", "address.city", "address.zip", "tags"}, mask.GetPaths())
+
+	t.Run("errors", func(t *testing.T) {
+		for _, path := range []string{"missing", "phone", "name.value", "address.missing"} {
+			_, err := ApplyMask[UserPatch](&fieldmaskpb.FieldMask{Paths: []string{path}}, msg)
+			require.Error(t, err, path)
+		}
+
+		_, err := ApplyMask[int](&fieldmaskpb.FieldMask{}, msg)
+		require.Error(t, err)
+	})
+}
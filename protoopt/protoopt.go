@@ -0,0 +1,625 @@
+// Package protoopt maps between proto messages and Go structs with [opt.Opt] fields.
+//
+// Use [Copy] to copy between a message and a struct in either direction.
+//
+// Struct fields are matched with proto fields by name, ignoring case and underscores,
+// e.g. DisplayName matches display_name. Use `proto:"name"` tags for renames and `proto:"-"` to skip fields.
+// Embedded structs without tags are flattened. Other struct fields without a matching proto field are errors.
+//
+// Supported field types:
+//   - Scalars and enums. Fields with presence, such as proto3 optional, oneof members and proto2 fields,
+//     are mapped to options using [protoreflect.Message.Has]
+//   - Wrapper types, e.g. google.protobuf.Int32Value, are mapped to options of their inner values
+//   - google.protobuf.Timestamp and google.protobuf.Duration are mapped to [time.Time] and [time.Duration]
+//   - Messages are mapped to options of generated message pointers, following [opt.FromProto], or to nested structs
+//   - Repeated fields are mapped to slices of the above
+//
+// Patch structs can be converted to and from field masks with [MaskOf] and [ApplyMask].
+package protoopt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+var (
+	messageType  = reflect.TypeFor[proto.Message]()
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+	bytesType    = reflect.TypeFor[[]byte]()
+)
+
+const (
+	timestampName protoreflect.FullName = "google.protobuf.Timestamp"
+	durationName  protoreflect.FullName = "google.protobuf.Duration"
+)
+
+// FieldError is an error of mapping a single field.
+type FieldError struct {
+	// Field is the name of the struct field
+	Field string
+
+	// Proto is the full name of the proto field, if matched
+	Proto protoreflect.FullName
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	if e.Proto == "" {
+		return fmt.Sprintf("protoopt: field %s: %v", e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("protoopt: field %s (%s): %v", e.Field, e.Proto, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Copy copies fields between a message and a struct with option fields, in either direction:
+//   - If dst is a message, src must be a struct (or a pointer to it), see [ToMessage]
+//   - If src is a message, dst must be a non-nil pointer to struct, see [FromMessage]
+//
+// Use [proto.Merge] to copy between messages.
+func Copy(dst, src any) error {
+	dstMsg, dstIsMsg := dst.(proto.Message)
+	srcMsg, srcIsMsg := src.(proto.Message)
+
+	switch {
+	case dstIsMsg && srcIsMsg:
+		return errors.New("protoopt: both dst and src are messages, use proto.Merge instead")
+	case dstIsMsg:
+		return ToMessage(dstMsg, src)
+	case srcIsMsg:
+		return FromMessage(dst, srcMsg)
+	default:
+		return fmt.Errorf("protoopt: either dst or src must be a message, got %T and %T", dst, src)
+	}
+}
+
+// FromMessage copies fields of the message into the struct pointed to by dst.
+//
+// Options of fields with presence are set to Some if the field is populated and to explicit None otherwise.
+// Options of fields without presence are always set to Some.
+func FromMessage(dst any, src proto.Message) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("protoopt: expected non-nil pointer to struct, got %T", dst)
+	}
+
+	return fromMessage(rv.Elem(), src.ProtoReflect())
+}
+
+// ToMessage copies fields of the struct (or a pointer to it) into the message.
+//
+// Implicit options leave the proto fields untouched, explicit None clears them
+// and Some sets them to the contained values.
+func ToMessage(dst proto.Message, src any) error {
+	rv := reflect.ValueOf(src)
+
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("protoopt: expected struct, got %T", src)
+	}
+
+	return toMessage(dst.ProtoReflect(), rv)
+}
+
+type field struct {
+	name  string
+	index []int
+	fd    protoreflect.FieldDescriptor
+}
+
+func mapFields(t reflect.Type, md protoreflect.MessageDescriptor) ([]field, error) {
+	byName := make(map[string]protoreflect.FieldDescriptor, md.Fields().Len())
+
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		byName[normalize(string(fd.Name()))] = fd
+	}
+
+	return appendFields(nil, nil, t, md, byName)
+}
+
+func appendFields(
+	result []field,
+	prefix []int,
+	t reflect.Type,
+	md protoreflect.MessageDescriptor,
+	byName map[string]protoreflect.FieldDescriptor,
+) ([]field, error) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), prefix...), i)
+
+		name, tagged := f.Tag.Lookup("proto")
+		if name == "-" {
+			continue
+		}
+
+		// embedded structs without tags are flattened
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct && f.Type != timeType && !optreflect.Is(f.Type) {
+			var err error
+
+			if result, err = appendFields(result, index, f.Type, md, byName); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if !tagged {
+			name = f.Name
+		}
+
+		fd, ok := byName[normalize(name)]
+		if !ok {
+			return nil, &FieldError{Field: f.Name, Err: fmt.Errorf("no matching field in %s", md.FullName())}
+		}
+
+		result = append(result, field{name: f.Name, index: index, fd: fd})
+	}
+
+	return result, nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+func fromMessage(dst reflect.Value, msg protoreflect.Message) error {
+	fields, err := mapFields(dst.Type(), msg.Descriptor())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		if err := fromField(dst.FieldByIndex(f.index), msg, f.fd); err != nil {
+			return &FieldError{Field: f.name, Proto: f.fd.FullName(), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func fromField(dst reflect.Value, msg protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	isOpt := optreflect.Is(dst.Type())
+
+	target := dst
+	if isOpt {
+		target = reflect.New(optreflect.Elem(dst.Type())).Elem()
+	}
+
+	if fd.IsMap() {
+		return errors.New("map fields are not supported")
+	}
+
+	if fd.IsList() {
+		if err := fromList(target, msg.Get(fd).List(), fd); err != nil {
+			return err
+		}
+
+		if isOpt {
+			optreflect.SetSome(dst, target)
+		}
+
+		return nil
+	}
+
+	present := !fd.HasPresence() || msg.Has(fd)
+
+	if wrapped := wrapperValue(fd); wrapped != nil {
+		present = msg.Has(fd)
+
+		if present {
+			inner := msg.Get(fd).Message()
+
+			if err := fromSingular(target, inner.Get(wrapped), wrapped); err != nil {
+				return err
+			}
+		}
+	} else if present {
+		if err := fromSingular(target, msg.Get(fd), fd); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case !isOpt:
+		if !present {
+			dst.SetZero()
+		}
+	case present:
+		optreflect.SetSome(dst, target)
+	default:
+		optreflect.SetNone(dst)
+	}
+
+	return nil
+}
+
+func fromList(dst reflect.Value, list protoreflect.List, fd protoreflect.FieldDescriptor) error {
+	if dst.Kind() != reflect.Slice || dst.Type() == bytesType {
+		return fmt.Errorf("cannot map repeated field into %s", dst.Type())
+	}
+
+	slice := reflect.MakeSlice(dst.Type(), list.Len(), list.Len())
+
+	for i := 0; i < list.Len(); i++ {
+		if err := fromSingular(slice.Index(i), list.Get(i), fd); err != nil {
+			return err
+		}
+	}
+
+	dst.Set(slice)
+
+	return nil
+}
+
+func fromSingular(dst reflect.Value, value protoreflect.Value, fd protoreflect.FieldDescriptor) error {
+	if fd.Message() == nil {
+		return fromScalar(dst, value, fd)
+	}
+
+	msg := value.Message()
+
+	switch {
+	case fd.Message().FullName() == timestampName && dst.Type() == timeType:
+		ts := &timestamppb.Timestamp{}
+		proto.Merge(ts, msg.Interface())
+
+		dst.Set(reflect.ValueOf(ts.AsTime()))
+	case fd.Message().FullName() == durationName && dst.Type() == durationType:
+		d := &durationpb.Duration{}
+		proto.Merge(d, msg.Interface())
+
+		dst.Set(reflect.ValueOf(d.AsDuration()))
+	case dst.Type().Implements(messageType):
+		m := msg.Interface()
+
+		// see opt.FromProto
+		if !msg.IsValid() {
+			dst.SetZero()
+
+			return nil
+		}
+
+		if reflect.TypeOf(m).AssignableTo(dst.Type()) {
+			dst.Set(reflect.ValueOf(m))
+
+			return nil
+		}
+
+		// same message with a different implementation, e.g. dynamicpb
+		if dst.Kind() != reflect.Pointer {
+			return fmt.Errorf("cannot map %s into %s", fd.Message().FullName(), dst.Type())
+		}
+
+		target := reflect.New(dst.Type().Elem()).Interface().(proto.Message)
+
+		if target.ProtoReflect().Descriptor().FullName() != fd.Message().FullName() {
+			return fmt.Errorf("cannot map %s into %s", fd.Message().FullName(), dst.Type())
+		}
+
+		proto.Merge(target, m)
+		dst.Set(reflect.ValueOf(target))
+	case dst.Kind() == reflect.Struct:
+		return fromMessage(dst, msg)
+	case dst.Kind() == reflect.Pointer && dst.Type().Elem().Kind() == reflect.Struct:
+		if !msg.IsValid() {
+			dst.SetZero()
+
+			return nil
+		}
+
+		dst.Set(reflect.New(dst.Type().Elem()))
+
+		return fromMessage(dst.Elem(), msg)
+	default:
+		return fmt.Errorf("cannot map %s into %s", fd.Message().FullName(), dst.Type())
+	}
+
+	return nil
+}
+
+func fromScalar(dst reflect.Value, value protoreflect.Value, fd protoreflect.FieldDescriptor) error {
+	var src reflect.Value
+
+	if fd.Kind() == protoreflect.EnumKind {
+		src = reflect.ValueOf(int32(value.Enum()))
+	} else {
+		src = reflect.ValueOf(value.Interface())
+	}
+
+	converted, err := convert(src, dst.Type())
+	if err != nil {
+		return fmt.Errorf("cannot map %s into %s: %w", fd.Kind(), dst.Type(), err)
+	}
+
+	dst.Set(converted)
+
+	return nil
+}
+
+type class int
+
+const (
+	classOther class = iota
+	classInt
+	classUint
+	classFloat
+	classString
+	classBool
+	classBytes
+)
+
+func classOf(t reflect.Type) class {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return classInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return classUint
+	case reflect.Float32, reflect.Float64:
+		return classFloat
+	case reflect.String:
+		return classString
+	case reflect.Bool:
+		return classBool
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return classBytes
+		}
+	}
+
+	return classOther
+}
+
+// convert converts scalar value into the given type of the same class, e.g. int32 into int, checking for overflows.
+func convert(src reflect.Value, t reflect.Type) (reflect.Value, error) {
+	c := classOf(src.Type())
+
+	if c == classOther || c != classOf(t) {
+		return reflect.Value{}, errors.New("incompatible types")
+	}
+
+	dst := reflect.New(t).Elem()
+
+	switch c {
+	case classInt:
+		if dst.OverflowInt(src.Int()) {
+			return reflect.Value{}, fmt.Errorf("%d overflows", src.Int())
+		}
+
+		dst.SetInt(src.Int())
+	case classUint:
+		if dst.OverflowUint(src.Uint()) {
+			return reflect.Value{}, fmt.Errorf("%d overflows", src.Uint())
+		}
+
+		dst.SetUint(src.Uint())
+	case classFloat:
+		if dst.OverflowFloat(src.Float()) {
+			return reflect.Value{}, fmt.Errorf("%g overflows", src.Float())
+		}
+
+		dst.SetFloat(src.Float())
+	case classString:
+		dst.SetString(src.String())
+	case classBool:
+		dst.SetBool(src.Bool())
+	case classBytes:
+		dst.SetBytes(append([]byte(nil), src.Bytes()...))
+	}
+
+	return dst, nil
+}
+
+func toMessage(msg protoreflect.Message, src reflect.Value) error {
+	fields, err := mapFields(src.Type(), msg.Descriptor())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		if err := toField(msg, f.fd, src.FieldByIndex(f.index)); err != nil {
+			return &FieldError{Field: f.name, Proto: f.fd.FullName(), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func toField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, src reflect.Value) error {
+	if optreflect.Is(src.Type()) {
+		if !optreflect.IsExplicit(src) {
+			return nil
+		}
+
+		value, ok := optreflect.Get(src)
+		if !ok {
+			msg.Clear(fd)
+
+			return nil
+		}
+
+		src = value
+	}
+
+	if fd.IsMap() {
+		return errors.New("map fields are not supported")
+	}
+
+	if fd.IsList() {
+		if src.Kind() != reflect.Slice || src.Type() == bytesType {
+			return fmt.Errorf("cannot map %s into repeated field", src.Type())
+		}
+
+		msg.Clear(fd)
+
+		list := msg.Mutable(fd).List()
+
+		for i := 0; i < src.Len(); i++ {
+			value, err := toSingular(list.NewElement, src.Index(i), fd)
+			if err != nil {
+				return err
+			}
+
+			list.Append(value)
+		}
+
+		return nil
+	}
+
+	if wrapped := wrapperValue(fd); wrapped != nil {
+		inner := msg.NewField(fd).Message()
+
+		value, err := toSingular(nil, src, wrapped)
+		if err != nil {
+			return err
+		}
+
+		inner.Set(wrapped, value)
+		msg.Set(fd, protoreflect.ValueOfMessage(inner))
+
+		return nil
+	}
+
+	if fd.Message() != nil && src.Kind() == reflect.Pointer && src.IsNil() {
+		msg.Clear(fd)
+
+		return nil
+	}
+
+	value, err := toSingular(func() protoreflect.Value { return msg.NewField(fd) }, src, fd)
+	if err != nil {
+		return err
+	}
+
+	msg.Set(fd, value)
+
+	return nil
+}
+
+func toSingular(newMessage func() protoreflect.Value, src reflect.Value, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	if fd.Message() == nil {
+		return toScalar(src, fd)
+	}
+
+	var m proto.Message
+
+	switch {
+	case fd.Message().FullName() == timestampName && src.Type() == timeType:
+		m = timestamppb.New(src.Interface().(time.Time))
+	case fd.Message().FullName() == durationName && src.Type() == durationType:
+		m = durationpb.New(src.Interface().(time.Duration))
+	case src.Type().Implements(messageType):
+		if src.Kind() == reflect.Pointer && src.IsNil() {
+			return protoreflect.Value{}, errors.New("nil message in repeated field")
+		}
+
+		m = src.Interface().(proto.Message)
+
+		if m.ProtoReflect().Descriptor().FullName() != fd.Message().FullName() {
+			return protoreflect.Value{}, fmt.Errorf("cannot map %s into %s", src.Type(), fd.Message().FullName())
+		}
+	case src.Kind() == reflect.Struct, src.Kind() == reflect.Pointer && src.Type().Elem().Kind() == reflect.Struct:
+		if src.Kind() == reflect.Pointer {
+			src = src.Elem()
+		}
+
+		value := newMessage()
+
+		if err := toMessage(value.Message(), src); err != nil {
+			return protoreflect.Value{}, err
+		}
+
+		return value, nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("cannot map %s into %s", src.Type(), fd.Message().FullName())
+	}
+
+	// the message is copied, as the field may use a different implementation, e.g. dynamicpb
+	value := newMessage()
+	proto.Merge(value.Message().Interface(), m)
+
+	return value, nil
+}
+
+func toScalar(src reflect.Value, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	t := scalarType(fd.Kind())
+	if t == nil {
+		return protoreflect.Value{}, fmt.Errorf("unsupported kind %s", fd.Kind())
+	}
+
+	converted, err := convert(src, t)
+	if err != nil {
+		return protoreflect.Value{}, fmt.Errorf("cannot map %s into %s: %w", src.Type(), fd.Kind(), err)
+	}
+
+	if fd.Kind() == protoreflect.EnumKind {
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(converted.Int())), nil
+	}
+
+	return protoreflect.ValueOf(converted.Interface()), nil
+}
+
+// scalarType returns the Go type used by protoreflect for the scalar kind.
+func scalarType(kind protoreflect.Kind) reflect.Type {
+	switch kind {
+	case protoreflect.BoolKind:
+		return reflect.TypeFor[bool]()
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return reflect.TypeFor[int32]()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return reflect.TypeFor[int64]()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return reflect.TypeFor[uint32]()
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return reflect.TypeFor[uint64]()
+	case protoreflect.FloatKind:
+		return reflect.TypeFor[float32]()
+	case protoreflect.DoubleKind:
+		return reflect.TypeFor[float64]()
+	case protoreflect.StringKind:
+		return reflect.TypeFor[string]()
+	case protoreflect.BytesKind:
+		return reflect.TypeFor[[]byte]()
+	default:
+		return nil
+	}
+}
+
+// wrapperValue returns the value field of the wrapper type or nil if the field is not a wrapper.
+func wrapperValue(fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.Message() == nil || fd.IsList() || fd.Message().ParentFile().Package() != "google.protobuf" ||
+		!strings.HasSuffix(string(fd.Message().Name()), "Value") {
+		return nil
+	}
+
+	switch fd.Message().Name() {
+	case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value",
+		"BoolValue", "StringValue", "BytesValue":
+		return fd.Message().Fields().ByName("value")
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,300 @@
+package protoopt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/metafates/opt"
+)
+
+// userDescriptor builds the following message:
+//
+//	syntax = "proto3";
+//
+//	message Address {
+//	  string city = 1;
+//	  optional string zip = 2;
+//	}
+//
+//	enum Status {
+//	  STATUS_UNSPECIFIED = 0;
+//	  STATUS_ACTIVE = 1;
+//	}
+//
+//	message User {
+//	  string name = 1;
+//	  optional int32 age = 2;
+//	  optional string nickname = 3;
+//	  google.protobuf.StringValue email = 4;
+//	  google.protobuf.Timestamp created_at = 5;
+//	  Address address = 6;
+//	  repeated string tags = 7;
+//	  Status status = 8;
+//	  oneof contact {
+//	    string phone = 9;
+//	    string telegram = 10;
+//	  }
+//	  google.protobuf.Timestamp updated_at = 11;
+//	}
+func userDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	optional := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, oneof int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:           proto.String(name),
+			Number:         proto.Int32(number),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           typ.Enum(),
+			OneofIndex:     proto.Int32(oneof),
+			Proto3Optional: proto.Bool(true),
+		}
+	}
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+
+		return f
+	}
+
+	tags := field("tags", 7, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+	tags.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	phone := field("phone", 9, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+	phone.OneofIndex = proto.Int32(0)
+
+	telegram := field("telegram", 10, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+	telegram.OneofIndex = proto.Int32(0)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/user.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/wrappers.proto", "google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					optional("zip", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, 0),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_zip")}},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					optional("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, 1),
+					optional("nickname", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, 2),
+					field("email", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.StringValue"),
+					field("created_at", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+					field("address", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.Address"),
+					tags,
+					field("status", 8, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.Status"),
+					phone,
+					telegram,
+					field("updated_at", 11, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{
+					{Name: proto.String("contact")},
+					{Name: proto.String("_age")},
+					{Name: proto.String("_nickname")},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	return fd.Messages().ByName("User")
+}
+
+type Address struct {
+	City string          `proto:"city"`
+	Zip  opt.Opt[string] `proto:"zip"`
+}
+
+type Status int32
+
+type User struct {
+	Name      string                          `proto:"name"`
+	Age       opt.Opt[int]                    `proto:"age"`
+	Nick      opt.Opt[string]                 `proto:"nickname"`
+	Email     opt.Opt[string]                 `proto:"email"`
+	CreatedAt opt.Opt[time.Time]              `proto:"created_at"`
+	Address   opt.Opt[Address]                `proto:"address"`
+	Tags      []string                        `proto:"tags"`
+	Status    Status                          `proto:"status"`
+	Phone     opt.Opt[string]                 `proto:"phone"`
+	Telegram  opt.Opt[string]                 `proto:"telegram"`
+	UpdatedAt opt.Opt[*timestamppb.Timestamp] `proto:"updated_at"`
+	Internal  string                          `proto:"-"`
+}
+
+func TestFromMessage(t *testing.T) {
+	md := userDescriptor(t)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("name"), protoreflect.ValueOfString("bob"))
+	msg.Set(md.Fields().ByName("age"), protoreflect.ValueOfInt32(0))
+	msg.Set(md.Fields().ByName("email"), protoreflect.ValueOfMessage(dynamicMessage(t, md.Fields().ByName("email"), wrapperspb.String("bob@example.com"))))
+	msg.Set(md.Fields().ByName("created_at"), protoreflect.ValueOfMessage(dynamicMessage(t, md.Fields().ByName("created_at"), timestamppb.New(created))))
+	msg.Set(md.Fields().ByName("updated_at"), protoreflect.ValueOfMessage(dynamicMessage(t, md.Fields().ByName("updated_at"), timestamppb.New(created))))
+	msg.Set(md.Fields().ByName("status"), protoreflect.ValueOfEnum(1))
+	msg.Set(md.Fields().ByName("telegram"), protoreflect.ValueOfString("@bob"))
+
+	tags := msg.Mutable(md.Fields().ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+
+	var user User
+
+	require.NoError(t, FromMessage(&user, msg))
+
+	require.Equal(t, "bob", user.Name)
+	require.Equal(t, opt.Some(0), user.Age)
+	require.Equal(t, opt.None[string](), user.Nick)
+	require.Equal(t, opt.Some("bob@example.com"), user.Email)
+	require.Equal(t, opt.Some(created), user.CreatedAt)
+	require.Equal(t, opt.None[Address](), user.Address)
+	require.Equal(t, []string{"a"}, user.Tags)
+	require.Equal(t, Status(1), user.Status)
+	require.Equal(t, opt.None[string](), user.Phone)
+	require.Equal(t, opt.Some("@bob"), user.Telegram)
+	require.True(t, proto.Equal(timestamppb.New(created), user.UpdatedAt.MustGet()))
+}
+
+func TestToMessage(t *testing.T) {
+	md := userDescriptor(t)
+
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("nickname"), protoreflect.ValueOfString("old"))
+	msg.Set(md.Fields().ByName("phone"), protoreflect.ValueOfString("123"))
+
+	user := User{
+		Name:    "bob",
+		Age:     opt.Some(30),
+		Nick:    opt.None[string](),
+		Email:   opt.Some("bob@example.com"),
+		Address: opt.Some(Address{City: "Paris", Zip: opt.Some("75001")}),
+		Tags:    []string{"a", "b"},
+		Status:  1,
+	}
+
+	require.NoError(t, ToMessage(msg, user))
+
+	require.Equal(t, "bob", msg.Get(md.Fields().ByName("name")).String())
+	require.Equal(t, int64(30), msg.Get(md.Fields().ByName("age")).Int())
+	require.False(t, msg.Has(md.Fields().ByName("nickname")))
+	require.Equal(t, "123", msg.Get(md.Fields().ByName("phone")).String(), "implicit options are left untouched")
+	require.False(t, msg.Has(md.Fields().ByName("created_at")))
+	require.Equal(t, 2, msg.Get(md.Fields().ByName("tags")).List().Len())
+	require.Equal(t, protoreflect.EnumNumber(1), msg.Get(md.Fields().ByName("status")).Enum())
+
+	var decoded User
+
+	require.NoError(t, FromMessage(&decoded, msg))
+
+	require.Equal(t, user.Email, decoded.Email)
+	require.Equal(t, user.Address, decoded.Address)
+}
+
+func TestErrors(t *testing.T) {
+	md := userDescriptor(t)
+	msg := dynamicpb.NewMessage(md)
+
+	t.Run("unknown field", func(t *testing.T) {
+		var v struct {
+			Missing opt.Opt[string]
+		}
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, FromMessage(&v, msg), &fieldErr)
+		require.Equal(t, "Missing", fieldErr.Field)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		var v struct {
+			Name opt.Opt[int]
+		}
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, FromMessage(&v, msg), &fieldErr)
+		require.Equal(t, protoreflect.FullName("test.User.name"), fieldErr.Proto)
+
+		require.Error(t, ToMessage(msg, struct{ Age opt.Opt[string] }{Age: opt.Some("x")}))
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		require.Error(t, ToMessage(msg, struct{ Age opt.Opt[int] }{Age: opt.Some(1 << 40)}))
+	})
+}
+
+func TestCopy(t *testing.T) {
+	md := userDescriptor(t)
+
+	type Base struct {
+		Name string
+		Skip int `proto:"-"`
+	}
+
+	type Patch struct {
+		Base
+
+		Age opt.Opt[int]
+	}
+
+	msg := dynamicpb.NewMessage(md)
+
+	require.NoError(t, Copy(msg, Patch{Base: Base{Name: "bob", Skip: 1}, Age: opt.Some(30)}))
+	require.Equal(t, "bob", msg.Get(md.Fields().ByName("name")).String())
+	require.Equal(t, int64(30), msg.Get(md.Fields().ByName("age")).Int())
+
+	var patch Patch
+
+	require.NoError(t, Copy(&patch, msg))
+	require.Equal(t, Patch{Base: Base{Name: "bob"}, Age: opt.Some(30)}, patch)
+
+	require.Error(t, Copy(msg, msg))
+	require.Error(t, Copy(&patch, patch))
+}
+
+// dynamicMessage copies the message into a dynamic message of the field type.
+func dynamicMessage(t *testing.T, fd protoreflect.FieldDescriptor, m proto.Message) protoreflect.Message {
+	t.Helper()
+
+	dynamic := dynamicpb.NewMessage(fd.Message())
+
+	b, err := proto.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, proto.Unmarshal(b, dynamic))
+
+	return dynamic
+}
@@ -0,0 +1,37 @@
+package opt
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/metafates/opt/internal/randvalue"
+)
+
+// Generate implements the [quick.Generator] interface, so options can be used in property-based tests.
+//
+// Generated options are implicit None, explicit None or Some with equal probability.
+// Values are generated in the same way as [quick.Value] does, using the Generate method of T if implemented.
+//
+// Panics if T is not supported, e.g. channels and functions.
+//
+// [quick.Generator]: https://pkg.go.dev/testing/quick#Generator
+// [quick.Value]: https://pkg.go.dev/testing/quick#Value
+func (o Opt[T]) Generate(rand *rand.Rand, size int) reflect.Value {
+	var generated Opt[T]
+
+	switch rand.Intn(3) {
+	case 0:
+	case 1:
+		generated = None[T]()
+	default:
+		value, ok := randvalue.Value(reflect.TypeFor[T](), rand, size)
+		if !ok {
+			panic(fmt.Sprintf("opt: cannot generate value of type %s", reflect.TypeFor[T]()))
+		}
+
+		generated = Some(value.Interface().(T))
+	}
+
+	return reflect.ValueOf(generated)
+}
@@ -0,0 +1,33 @@
+package opt
+
+import (
+	"reflect"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+func init() {
+	optreflect.Register(func(option, value reflect.Value, some bool) {
+		option.Addr().Interface().(reflectSetter).setReflect(value, some)
+	})
+}
+
+// reflectSetter is implemented by option pointers,
+// so that subpackages could set options of unknown type through [optreflect].
+type reflectSetter interface {
+	setReflect(value reflect.Value, some bool)
+}
+
+func (o *Opt[T]) setReflect(value reflect.Value, some bool) {
+	if !some {
+		*o = None[T]()
+
+		return
+	}
+
+	var v T
+
+	reflect.ValueOf(&v).Elem().Set(value)
+
+	*o = Some(v)
+}
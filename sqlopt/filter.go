@@ -0,0 +1,494 @@
+package sqlopt
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operator is a comparison used by filter fields, configured with the `op:"..."` tag.
+type Operator string
+
+const (
+	// Eq matches values equal to the option value.
+	Eq Operator = "eq"
+
+	// Ne matches values not equal to the option value.
+	Ne Operator = "ne"
+
+	// Lt matches values less than the option value.
+	Lt Operator = "lt"
+
+	// Lte matches values less than or equal to the option value.
+	Lte Operator = "lte"
+
+	// Gt matches values greater than the option value.
+	Gt Operator = "gt"
+
+	// Gte matches values greater than or equal to the option value.
+	Gte Operator = "gte"
+
+	// Like matches strings against the SQL LIKE pattern in the option value.
+	Like Operator = "like"
+
+	// In matches values equal to any element of the option slice value.
+	In Operator = "in"
+)
+
+var operatorSQL = map[Operator]string{
+	Eq:   "=",
+	Ne:   "<>",
+	Lt:   "<",
+	Lte:  "<=",
+	Gt:   ">",
+	Gte:  ">=",
+	Like: "LIKE",
+}
+
+// Where compiles filter struct into parameterized condition (without the WHERE keyword)
+// joining constraints with AND. Returns empty string if there are no constraints.
+//
+// Filter fields are options mapped to columns with `db:"column"` tags
+// and an optional `op:"operator"` tag, which defaults to [Eq]:
+//   - Implicit None means no constraint
+//   - Explicit None means `column IS NULL` or `column IS NOT NULL` for [Ne]
+//   - Some means `column <op> value`. [In] operator requires a slice value
+//
+// Placeholders are numbered from 1. [AtName] style uses `@p1`, `@p2`, ... names.
+//
+// See [Predicate] for the equivalent in-memory filter.
+func (p Placeholder) Where(filter any) (string, []any, error) {
+	conditions, err := compileFilter(filter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		clauses = make([]string, 0, len(conditions))
+		args    []any
+	)
+
+	bind := func(value any) string {
+		n := len(args) + 1
+		name := "p" + strconv.Itoa(n)
+
+		args = append(args, p.arg(name, value))
+
+		return p.placeholder(name, n)
+	}
+
+	for _, c := range conditions {
+		switch {
+		case c.isNull && c.op == Ne:
+			clauses = append(clauses, c.column+" IS NOT NULL")
+		case c.isNull:
+			clauses = append(clauses, c.column+" IS NULL")
+		case c.op == In && len(c.values) == 0:
+			clauses = append(clauses, "1 = 0")
+		case c.op == In:
+			placeholders := make([]string, 0, len(c.values))
+
+			for _, v := range c.values {
+				placeholders = append(placeholders, bind(v))
+			}
+
+			clauses = append(clauses, c.column+" IN ("+strings.Join(placeholders, ", ")+")")
+		default:
+			clauses = append(clauses, c.column+" "+operatorSQL[c.op]+" "+bind(c.values[0]))
+		}
+	}
+
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// Predicate compiles filter struct into in-memory predicate over rows of type S,
+// which must be a struct with `db:"column"` tagged fields for every constrained column.
+//
+// The predicate follows SQL semantics of the [Placeholder.Where] condition,
+// including NULL comparisons always being false. Row NULLs are represented as
+// [opt.None] options or nil pointers.
+//
+// [Like] supports `%` and `_` wildcards escaped with a backslash and is case-sensitive.
+func Predicate[S any](filter any) (func(row S) bool, error) {
+	conditions, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rowType := reflect.TypeFor[S]()
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlopt: expected struct row, got %s", rowType)
+	}
+
+	indices := make([][]int, len(conditions))
+
+	for i, c := range conditions {
+		index, ok := columnIndex(rowType, c.column)
+		if !ok {
+			return nil, fmt.Errorf("sqlopt: row %s has no column %q", rowType, c.column)
+		}
+
+		indices[i] = index
+	}
+
+	return func(row S) bool {
+		value := reflect.ValueOf(row)
+
+		for i, c := range conditions {
+			field := value.FieldByIndex(indices[i])
+
+			if !c.match(driverValue(field.Interface())) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+type condition struct {
+	column string
+	op     Operator
+	isNull bool
+	values []driver.Value
+}
+
+func (c condition) match(value driver.Value) bool {
+	if c.isNull {
+		return (value == nil) != (c.op == Ne)
+	}
+
+	if value == nil {
+		return false
+	}
+
+	switch c.op {
+	case In:
+		for _, v := range c.values {
+			if cmp, ok := compare(value, v); ok && cmp == 0 {
+				return true
+			}
+		}
+
+		return false
+	case Like:
+		s, ok := asString(value)
+		if !ok {
+			return false
+		}
+
+		pattern, ok := asString(c.values[0])
+
+		return ok && like(pattern, s)
+	}
+
+	cmp, ok := compare(value, c.values[0])
+	if !ok {
+		return false
+	}
+
+	switch c.op {
+	case Eq:
+		return cmp == 0
+	case Ne:
+		return cmp != 0
+	case Lt:
+		return cmp < 0
+	case Lte:
+		return cmp <= 0
+	case Gt:
+		return cmp > 0
+	case Gte:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+func compileFilter(filter any) ([]condition, error) {
+	value := reflect.ValueOf(filter)
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, errors.New("sqlopt: nil pointer")
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlopt: expected struct, got %T", filter)
+	}
+
+	var conditions []condition
+
+	if err := appendConditions(&conditions, value); err != nil {
+		return nil, err
+	}
+
+	return conditions, nil
+}
+
+func appendConditions(conditions *[]condition, value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		column, tagged := field.Tag.Lookup("db")
+		column, _, _ = strings.Cut(column, ",")
+
+		// embedded structs without tags are flattened, the same as in [Placeholder.Update]
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if err := appendConditions(conditions, value.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if column == "" || column == "-" || !field.IsExported() {
+			continue
+		}
+
+		option, ok := value.Field(i).Interface().(optional)
+		if !ok {
+			return fmt.Errorf("sqlopt: filter field %s is not an option", field.Name)
+		}
+
+		op := Operator(field.Tag.Get("op"))
+		if op == "" {
+			op = Eq
+		}
+
+		if _, ok := operatorSQL[op]; !ok && op != In {
+			return fmt.Errorf("sqlopt: filter field %s: unknown operator %q", field.Name, op)
+		}
+
+		if !option.IsExplicit() {
+			continue
+		}
+
+		c := condition{column: column, op: op}
+
+		raw, err := option.Value()
+		if err != nil {
+			return fmt.Errorf("sqlopt: filter field %s: %w", field.Name, err)
+		}
+
+		switch {
+		case raw == nil:
+			c.isNull = true
+		case op == In:
+			if c.values, err = sliceValues(raw); err != nil {
+				return fmt.Errorf("sqlopt: filter field %s: %w", field.Name, err)
+			}
+		default:
+			c.values = []driver.Value{driverValue(raw)}
+		}
+
+		*conditions = append(*conditions, c)
+	}
+
+	return nil
+}
+
+func sliceValues(raw any) ([]driver.Value, error) {
+	slice := reflect.ValueOf(raw)
+
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return nil, fmt.Errorf("operator in requires a slice, got %T", raw)
+	}
+
+	values := make([]driver.Value, 0, slice.Len())
+
+	for i := 0; i < slice.Len(); i++ {
+		values = append(values, driverValue(slice.Index(i).Interface()))
+	}
+
+	return values, nil
+}
+
+// driverValue normalizes value to one of the [driver.Value] types where possible,
+// so that values of different go types could be compared.
+func driverValue(value any) driver.Value {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err == nil {
+		return converted
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil {
+			return v
+		}
+	}
+
+	return value
+}
+
+func compare(a, b driver.Value) (int, bool) {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return cmpOrdered(a, b), true
+		case float64:
+			return cmpOrdered(float64(a), b), true
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return cmpOrdered(a, float64(b)), true
+		case float64:
+			return cmpOrdered(a, b), true
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			if a == b {
+				return 0, true
+			}
+
+			if b {
+				return -1, true
+			}
+
+			return 1, true
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b), true
+		}
+	case []byte:
+		if b, ok := b.([]byte); ok {
+			return bytes.Compare(a, b), true
+		}
+
+		if b, ok := b.(string); ok {
+			return strings.Compare(string(a), b), true
+		}
+	case string:
+		if b, ok := asString(b); ok {
+			return strings.Compare(a, b), true
+		}
+	}
+
+	if reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() {
+		if a == b {
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+func cmpOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func asString(value driver.Value) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case []byte:
+		return string(value), true
+	default:
+		return "", false
+	}
+}
+
+// like reports whether s matches SQL LIKE pattern.
+//
+// It uses the greedy two-pointer wildcard matching, backtracking only to the last `%`,
+// so that it runs in O(len(pattern) * len(s)) time.
+func like(pattern, s string) bool {
+	p := parseLike(pattern)
+	r := []rune(s)
+
+	var (
+		pi, si = 0, 0
+
+		// position of the last `%` in the pattern and the matching position in s
+		star, mark = -1, 0
+	)
+
+	for si < len(r) {
+		switch {
+		case pi < len(p) && !p[pi].literal && p[pi].r == '%':
+			star, mark = pi, si
+			pi++
+		case pi < len(p) && (!p[pi].literal && p[pi].r == '_' || p[pi].r == r[si]):
+			pi++
+			si++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			si = mark
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && !p[pi].literal && p[pi].r == '%' {
+		pi++
+	}
+
+	return pi == len(p)
+}
+
+type likeToken struct {
+	r       rune
+	literal bool
+}
+
+// parseLike resolves backslash escapes of the pattern, so that escaped wildcards become literals.
+func parseLike(pattern string) []likeToken {
+	runes := []rune(pattern)
+	tokens := make([]likeToken, 0, len(runes))
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\\' && i+1 < len(runes) {
+			i++
+
+			tokens = append(tokens, likeToken{r: runes[i], literal: true})
+
+			continue
+		}
+
+		tokens = append(tokens, likeToken{r: runes[i], literal: runes[i] != '%' && runes[i] != '_'})
+	}
+
+	return tokens
+}
+
+func columnIndex(t reflect.Type, column string) ([]int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, tagged := field.Tag.Lookup("db")
+		name, _, _ = strings.Cut(name, ",")
+
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if index, ok := columnIndex(field.Type, column); ok {
+				return append([]int{i}, index...), true
+			}
+
+			continue
+		}
+
+		if name == column && field.IsExported() {
+			return []int{i}, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,238 @@
+package sqlopt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+type Row struct {
+	ID    int             `db:"id"`
+	Name  string          `db:"name"`
+	Age   opt.Opt[int]    `db:"age"`
+	Email *string         `db:"email"`
+	Team  opt.Opt[string] `db:"team"`
+}
+
+type Filter struct {
+	IDs    opt.Opt[[]int]  `db:"id" op:"in"`
+	Name   opt.Opt[string] `db:"name" op:"like"`
+	MinAge opt.Opt[int]    `db:"age" op:"gte"`
+	MaxAge opt.Opt[int]    `db:"age" op:"lt"`
+	Email  opt.Opt[string] `db:"email"`
+	Team   opt.Opt[string] `db:"team" op:"ne"`
+}
+
+func TestFilter(t *testing.T) {
+	email := "bob@example.com"
+
+	rows := []Row{
+		{ID: 1, Name: "alice", Age: opt.Some(30), Team: opt.Some("core")},
+		{ID: 2, Name: "bob", Age: opt.Some(17), Email: &email},
+		{ID: 3, Name: "albert", Age: opt.None[int](), Team: opt.Some("infra")},
+		{ID: 4, Name: "al_x", Age: opt.Some(45), Team: opt.Some("core")},
+	}
+
+	testCases := []struct {
+		name      string
+		filter    Filter
+		wantQuery string
+		wantArgs  []any
+		wantIDs   []int
+	}{
+		{
+			name:      "no constraints",
+			filter:    Filter{},
+			wantQuery: "",
+			wantArgs:  nil,
+			wantIDs:   []int{1, 2, 3, 4},
+		},
+		{
+			name:      "in",
+			filter:    Filter{IDs: opt.Some([]int{1, 3})},
+			wantQuery: "id IN ($1, $2)",
+			wantArgs:  []any{int64(1), int64(3)},
+			wantIDs:   []int{1, 3},
+		},
+		{
+			name:      "empty in",
+			filter:    Filter{IDs: opt.Some([]int{})},
+			wantQuery: "1 = 0",
+			wantArgs:  nil,
+			wantIDs:   nil,
+		},
+		{
+			name:      "like",
+			filter:    Filter{Name: opt.Some("al%")},
+			wantQuery: "name LIKE $1",
+			wantArgs:  []any{"al%"},
+			wantIDs:   []int{1, 3, 4},
+		},
+		{
+			name:      "like escape",
+			filter:    Filter{Name: opt.Some(`al\_%`)},
+			wantQuery: "name LIKE $1",
+			wantArgs:  []any{`al\_%`},
+			wantIDs:   []int{4},
+		},
+		{
+			name:      "range skips nulls",
+			filter:    Filter{MinAge: opt.Some(18), MaxAge: opt.Some(40)},
+			wantQuery: "age >= $1 AND age < $2",
+			wantArgs:  []any{int64(18), int64(40)},
+			wantIDs:   []int{1},
+		},
+		{
+			name:      "is null",
+			filter:    Filter{Email: opt.None[string]()},
+			wantQuery: "email IS NULL",
+			wantArgs:  nil,
+			wantIDs:   []int{1, 3, 4},
+		},
+		{
+			name:      "is not null",
+			filter:    Filter{Team: opt.None[string]()},
+			wantQuery: "team IS NOT NULL",
+			wantArgs:  nil,
+			wantIDs:   []int{1, 3, 4},
+		},
+		{
+			name:      "ne skips nulls",
+			filter:    Filter{Team: opt.Some("core")},
+			wantQuery: "team <> $1",
+			wantArgs:  []any{"core"},
+			wantIDs:   []int{3},
+		},
+		{
+			name:      "eq pointer",
+			filter:    Filter{Email: opt.Some(email)},
+			wantQuery: "email = $1",
+			wantArgs:  []any{email},
+			wantIDs:   []int{2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args, err := Dollar.Where(tc.filter)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wantQuery, query)
+			require.Equal(t, tc.wantArgs, args)
+
+			predicate, err := Predicate[Row](tc.filter)
+			require.NoError(t, err)
+
+			var ids []int
+
+			for _, row := range rows {
+				if predicate(row) {
+					ids = append(ids, row.ID)
+				}
+			}
+
+			require.Equal(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func TestFilter_Embedded(t *testing.T) {
+	type Common struct {
+		Team opt.Opt[string] `db:"team"`
+	}
+
+	type EmbeddedFilter struct {
+		Common
+		Name opt.Opt[string] `db:"name"`
+	}
+
+	filter := EmbeddedFilter{Common: Common{Team: opt.Some("core")}, Name: opt.Some("alice")}
+
+	query, args, err := Dollar.Where(filter)
+	require.NoError(t, err)
+	require.Equal(t, "team = $1 AND name = $2", query)
+	require.Equal(t, []any{"core", "alice"}, args)
+
+	update, updateArgs, err := Dollar.Update("users", filter, "id = ?", 1)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE users SET team = $1, name = $2 WHERE id = $3", update)
+	require.Equal(t, []any{"core", "alice", 1}, updateArgs)
+
+	predicate, err := Predicate[Row](filter)
+	require.NoError(t, err)
+	require.True(t, predicate(Row{Name: "alice", Team: opt.Some("core")}))
+	require.False(t, predicate(Row{Name: "alice", Team: opt.Some("infra")}))
+}
+
+func TestFilter_Errors(t *testing.T) {
+	t.Run("unknown operator", func(t *testing.T) {
+		var filter struct {
+			Age opt.Opt[int] `db:"age" op:"between"`
+		}
+
+		_, _, err := Question.Where(filter)
+		require.Error(t, err)
+	})
+
+	t.Run("not an option", func(t *testing.T) {
+		var filter struct {
+			Age int `db:"age"`
+		}
+
+		_, _, err := Question.Where(filter)
+		require.Error(t, err)
+	})
+
+	t.Run("in requires slice", func(t *testing.T) {
+		filter := struct {
+			Age opt.Opt[int] `db:"age" op:"in"`
+		}{Age: opt.Some(1)}
+
+		_, _, err := Question.Where(filter)
+		require.Error(t, err)
+	})
+
+	t.Run("missing row column", func(t *testing.T) {
+		filter := struct {
+			Role opt.Opt[string] `db:"role"`
+		}{Role: opt.Some("admin")}
+
+		_, err := Predicate[Row](filter)
+		require.Error(t, err)
+	})
+}
+
+func TestLike(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"abc", "abc", true},
+		{"abc", "abd", false},
+		{"a%", "abc", true},
+		{"%c", "abc", true},
+		{"%b%", "abc", true},
+		{"a_c", "abc", true},
+		{"a_c", "ac", false},
+		{"%", "", true},
+		{`100\%`, "100%", true},
+		{`100\%`, "1000", false},
+		{`a\_c`, "a_c", true},
+		{`a\_c`, "abc", false},
+		{`a\`, `a\`, true},
+		{"%a%b", "xaxb", true},
+		{"a%%b", "ab", true},
+		{"%ab%", "aab", true},
+		{"_%_", "a", false},
+		{"ж_", "жы", true},
+		{strings.Repeat("%a", 30) + "b", strings.Repeat("a", 100), false},
+		{strings.Repeat("%a", 30) + "b", strings.Repeat("a", 100) + "b", true},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, like(tc.pattern, tc.s), "%q LIKE %q", tc.s, tc.pattern)
+	}
+}
@@ -0,0 +1,255 @@
+// Package sqlopt builds SQL statements for partial updates and inserts from structs with [opt.Opt] fields.
+//
+// Only explicitly set options are written, see [opt.Opt.IsExplicit]:
+//   - Implicit None is omitted from the statement
+//   - Explicit None is written as NULL
+//   - Some is written as its value
+//
+// Struct fields are mapped to columns with `db:"column"` tags, fields without tags or tagged with `db:"-"` are ignored.
+// Fields which are not options are always written.
+//
+// Filter structs with option fields can be compiled to WHERE conditions with [Placeholder.Where]
+// and to equivalent in-memory predicates with [Predicate].
+package sqlopt
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrNoColumns is returned when there are no columns to write.
+var ErrNoColumns = errors.New("sqlopt: no explicit columns")
+
+// Placeholder is a style of bind parameters used in the generated statements.
+type Placeholder int
+
+const (
+	// Question uses positional `?` placeholders, e.g. MySQL and SQLite.
+	Question Placeholder = iota
+
+	// Dollar uses numbered `$1` placeholders, e.g. PostgreSQL.
+	Dollar
+
+	// AtName uses named `@column` placeholders, e.g. SQL Server.
+	// Arguments are returned as [sql.NamedArg].
+	AtName
+)
+
+// Update returns parameterized `UPDATE table SET ... WHERE where` statement
+// containing only explicit fields of the patch, which must be a struct or a pointer to it.
+//
+// The where clause uses `?` placeholders for [Question] and [Dollar] styles, which are rewritten to
+// continue the numbering of the SET clause. For [AtName] style use `@name` placeholders with [sql.Named] arguments.
+// Empty where clause updates all the rows.
+//
+// Returns [ErrNoColumns] if there are no explicit fields.
+func (p Placeholder) Update(table string, patch any, where string, whereArgs ...any) (string, []any, error) {
+	columns, err := explicitColumns(patch)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		query strings.Builder
+		args  = make([]any, 0, len(columns)+len(whereArgs))
+	)
+
+	query.WriteString("UPDATE ")
+	query.WriteString(table)
+	query.WriteString(" SET ")
+
+	for i, c := range columns {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+
+		query.WriteString(c.name)
+		query.WriteString(" = ")
+		query.WriteString(p.placeholder(c.name, len(args)+1))
+
+		args = append(args, p.arg(c.name, c.value))
+	}
+
+	if where != "" {
+		query.WriteString(" WHERE ")
+		query.WriteString(p.rebind(where, len(args)))
+
+		args = append(args, whereArgs...)
+	}
+
+	return query.String(), args, nil
+}
+
+// Insert returns parameterized `INSERT INTO table (...) VALUES (...)` statement
+// omitting implicit fields of the row, which must be a struct or a pointer to it.
+//
+// Returns [ErrNoColumns] if there are no explicit fields.
+func (p Placeholder) Insert(table string, row any) (string, []any, error) {
+	columns, err := explicitColumns(row)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		names        = make([]string, 0, len(columns))
+		placeholders = make([]string, 0, len(columns))
+		args         = make([]any, 0, len(columns))
+	)
+
+	for _, c := range columns {
+		names = append(names, c.name)
+		placeholders = append(placeholders, p.placeholder(c.name, len(args)+1))
+		args = append(args, p.arg(c.name, c.value))
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		table,
+		strings.Join(names, ", "),
+		strings.Join(placeholders, ", "),
+	)
+
+	return query, args, nil
+}
+
+func (p Placeholder) placeholder(name string, n int) string {
+	switch p {
+	case Dollar:
+		return "$" + strconv.Itoa(n)
+	case AtName:
+		return "@" + name
+	default:
+		return "?"
+	}
+}
+
+func (p Placeholder) arg(name string, value any) any {
+	if p == AtName {
+		return sql.Named(name, value)
+	}
+
+	return value
+}
+
+// rebind rewrites `?` placeholders outside of quoted strings to the [Dollar] style
+// starting after the given offset.
+func (p Placeholder) rebind(clause string, offset int) string {
+	if p != Dollar {
+		return clause
+	}
+
+	var (
+		b     strings.Builder
+		quote byte
+	)
+
+	for i := 0; i < len(clause); i++ {
+		c := clause[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			offset++
+
+			b.WriteString("$" + strconv.Itoa(offset))
+
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+type optional interface {
+	IsExplicit() bool
+	driver.Valuer
+}
+
+type column struct {
+	name  string
+	value any
+}
+
+func explicitColumns(v any) ([]column, error) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, errors.New("sqlopt: nil pointer")
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlopt: expected struct, got %T", v)
+	}
+
+	var columns []column
+
+	if err := appendColumns(&columns, value); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, ErrNoColumns
+	}
+
+	return columns, nil
+}
+
+func appendColumns(columns *[]column, value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		name, tagged := field.Tag.Lookup("db")
+		name, _, _ = strings.Cut(name, ",")
+
+		// embedded structs without tags are flattened
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if err := appendColumns(columns, value.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !tagged || name == "-" || name == "" || !field.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i).Interface()
+
+		option, ok := fieldValue.(optional)
+		if !ok {
+			*columns = append(*columns, column{name: name, value: fieldValue})
+
+			continue
+		}
+
+		if !option.IsExplicit() {
+			continue
+		}
+
+		arg, err := option.Value()
+		if err != nil {
+			return fmt.Errorf("sqlopt: field %s: %w", field.Name, err)
+		}
+
+		*columns = append(*columns, column{name: name, value: arg})
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package sqlopt
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+type Base struct {
+	UpdatedBy opt.Opt[string] `db:"updated_by"`
+}
+
+type User struct {
+	Base
+
+	Name     opt.Opt[string] `db:"name"`
+	Age      opt.Opt[int]    `db:"age"`
+	Email    opt.Opt[string] `db:"email,omitempty"`
+	Version  int             `db:"version"`
+	Ignored  opt.Opt[string] `db:"-"`
+	Untagged opt.Opt[string]
+}
+
+func TestPlaceholder_Update(t *testing.T) {
+	patch := User{
+		Name:     opt.Some("bob"),
+		Age:      opt.None[int](),
+		Version:  2,
+		Ignored:  opt.Some("x"),
+		Untagged: opt.Some("y"),
+	}
+
+	testCases := []struct {
+		name        string
+		placeholder Placeholder
+		where       string
+		whereArgs   []any
+		wantQuery   string
+		wantArgs    []any
+	}{
+		{
+			name:        "question",
+			placeholder: Question,
+			where:       "id = ?",
+			whereArgs:   []any{42},
+			wantQuery:   "UPDATE users SET name = ?, age = ?, version = ? WHERE id = ?",
+			wantArgs:    []any{"bob", nil, 2, 42},
+		},
+		{
+			name:        "dollar",
+			placeholder: Dollar,
+			where:       "id = ? AND note <> '?'",
+			whereArgs:   []any{42},
+			wantQuery:   "UPDATE users SET name = $1, age = $2, version = $3 WHERE id = $4 AND note <> '?'",
+			wantArgs:    []any{"bob", nil, 2, 42},
+		},
+		{
+			name:        "at name",
+			placeholder: AtName,
+			where:       "id = @id",
+			whereArgs:   []any{sql.Named("id", 42)},
+			wantQuery:   "UPDATE users SET name = @name, age = @age, version = @version WHERE id = @id",
+			wantArgs: []any{
+				sql.Named("name", "bob"),
+				sql.Named("age", nil),
+				sql.Named("version", 2),
+				sql.Named("id", 42),
+			},
+		},
+		{
+			name:        "no where",
+			placeholder: Dollar,
+			wantQuery:   "UPDATE users SET name = $1, age = $2, version = $3",
+			wantArgs:    []any{"bob", nil, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args, err := tc.placeholder.Update("users", &patch, tc.where, tc.whereArgs...)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wantQuery, query)
+			require.Equal(t, tc.wantArgs, args)
+		})
+	}
+
+	t.Run("embedded", func(t *testing.T) {
+		query, args, err := Question.Update("users", User{Base: Base{UpdatedBy: opt.Some("admin")}, Version: 1}, "")
+
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET updated_by = ?, version = ?", query)
+		require.Equal(t, []any{"admin", 1}, args)
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		var patch struct {
+			Name opt.Opt[string] `db:"name"`
+		}
+
+		_, _, err := Question.Update("users", patch, "")
+
+		require.ErrorIs(t, err, ErrNoColumns)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, _, err := Question.Update("users", 42, "")
+
+		require.Error(t, err)
+	})
+}
+
+func TestPlaceholder_Insert(t *testing.T) {
+	row := User{
+		Name:  opt.Some("bob"),
+		Email: opt.None[string](),
+	}
+
+	query, args, err := Dollar.Insert("users", row)
+
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO users (name, email, version) VALUES ($1, $2, $3)", query)
+	require.Equal(t, []any{"bob", nil, 0}, args)
+}
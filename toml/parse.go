@@ -0,0 +1,856 @@
+package toml
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// table is a parsed TOML table.
+type table struct {
+	values map[string]any
+
+	// defined is set for tables defined with [header]
+	defined bool
+
+	// dotted is set for tables created by dotted keys
+	dotted bool
+
+	// inline is set for inline tables, which can not be extended
+	inline bool
+}
+
+func newTable() *table {
+	return &table{values: make(map[string]any)}
+}
+
+// tableArray is a parsed array of tables defined with [[header]].
+type tableArray struct {
+	tables []*table
+}
+
+type parser struct {
+	input   string
+	pos     int
+	line    int
+	root    *table
+	current *table
+}
+
+func parse(input string) (*table, error) {
+	if !utf8.ValidString(input) {
+		return nil, &ParseError{Line: 1, Column: 1, Msg: "invalid UTF-8"}
+	}
+
+	input = strings.TrimPrefix(input, "\uFEFF")
+
+	p := &parser{input: input, line: 1, root: newTable()}
+	p.current = p.root
+
+	if err := p.document(); err != nil {
+		return nil, err
+	}
+
+	return p.root, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	lineStart := strings.LastIndexByte(p.input[:p.pos], '\n') + 1
+
+	return &ParseError{
+		Line:   p.line,
+		Column: utf8.RuneCountInString(p.input[lineStart:p.pos]) + 1,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *parser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.input[p.pos:], prefix)
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) skipComment() error {
+	if p.peek() != '#' {
+		return nil
+	}
+
+	for !p.eof() && p.peek() != '\n' {
+		if p.hasPrefix("\r\n") {
+			return nil
+		}
+
+		if isControl(rune(p.peek())) && p.peek() != '\t' {
+			return p.errorf("control character in comment")
+		}
+
+		p.pos++
+	}
+
+	return nil
+}
+
+// newline consumes a newline and reports whether it was present.
+func (p *parser) newline() bool {
+	switch {
+	case p.hasPrefix("\n"):
+		p.pos++
+	case p.hasPrefix("\r\n"):
+		p.pos += 2
+	default:
+		return false
+	}
+
+	p.line++
+
+	return true
+}
+
+// endOfLine consumes whitespace, comment and newline after an expression.
+func (p *parser) endOfLine() error {
+	p.skipSpaces()
+
+	if err := p.skipComment(); err != nil {
+		return err
+	}
+
+	if p.eof() || p.newline() {
+		return nil
+	}
+
+	return p.errorf("expected newline, got %q", p.peek())
+}
+
+// skipBlank skips whitespace, comments and newlines inside arrays.
+func (p *parser) skipBlank() error {
+	for {
+		p.skipSpaces()
+
+		if err := p.skipComment(); err != nil {
+			return err
+		}
+
+		if !p.newline() {
+			return nil
+		}
+	}
+}
+
+func (p *parser) document() error {
+	for {
+		p.skipSpaces()
+
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+
+		switch {
+		case p.peek() == '#' || p.peek() == '\n' || p.hasPrefix("\r\n"):
+			err = p.endOfLine()
+		case p.hasPrefix("[["):
+			err = p.arrayTableHeader()
+		case p.peek() == '[':
+			err = p.tableHeader()
+		default:
+			err = p.keyValue(p.current)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) tableHeader() error {
+	p.pos++
+	p.skipSpaces()
+
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+
+	p.skipSpaces()
+
+	if p.peek() != ']' {
+		return p.errorf("expected ']' after table header")
+	}
+
+	p.pos++
+
+	parent, err := p.walkHeader(key[:len(key)-1])
+	if err != nil {
+		return err
+	}
+
+	name := key[len(key)-1]
+
+	switch existing := parent.values[name].(type) {
+	case nil:
+		t := newTable()
+		t.defined = true
+		parent.values[name] = t
+		p.current = t
+	case *table:
+		if existing.defined || existing.dotted || existing.inline {
+			return p.errorf("table %s is already defined", Key(key))
+		}
+
+		existing.defined = true
+		p.current = existing
+	default:
+		return p.errorf("key %s is already defined", Key(key))
+	}
+
+	return p.endOfLine()
+}
+
+func (p *parser) arrayTableHeader() error {
+	p.pos += 2
+	p.skipSpaces()
+
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+
+	p.skipSpaces()
+
+	if !p.hasPrefix("]]") {
+		return p.errorf("expected ']]' after array of tables header")
+	}
+
+	p.pos += 2
+
+	parent, err := p.walkHeader(key[:len(key)-1])
+	if err != nil {
+		return err
+	}
+
+	name := key[len(key)-1]
+	t := newTable()
+	t.defined = true
+
+	switch existing := parent.values[name].(type) {
+	case nil:
+		parent.values[name] = &tableArray{tables: []*table{t}}
+	case *tableArray:
+		existing.tables = append(existing.tables, t)
+	default:
+		return p.errorf("key %s is already defined", Key(key))
+	}
+
+	p.current = t
+
+	return p.endOfLine()
+}
+
+// walkHeader returns the parent table for the header, creating implicit tables.
+func (p *parser) walkHeader(key []string) (*table, error) {
+	t := p.root
+
+	for i, name := range key {
+		switch next := t.values[name].(type) {
+		case nil:
+			created := newTable()
+			t.values[name] = created
+			t = created
+		case *table:
+			if next.inline {
+				return nil, p.errorf("inline table %s can not be extended", Key(key[:i+1]))
+			}
+
+			t = next
+		case *tableArray:
+			t = next.tables[len(next.tables)-1]
+		default:
+			return nil, p.errorf("key %s is not a table", Key(key[:i+1]))
+		}
+	}
+
+	return t, nil
+}
+
+func (p *parser) keyValue(t *table) error {
+	if err := p.inlineKeyValue(t); err != nil {
+		return err
+	}
+
+	return p.endOfLine()
+}
+
+func (p *parser) inlineKeyValue(t *table) error {
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+
+	p.skipSpaces()
+
+	if p.peek() != '=' {
+		return p.errorf("expected '=' after key")
+	}
+
+	p.pos++
+	p.skipSpaces()
+
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	for i, name := range key[:len(key)-1] {
+		switch next := t.values[name].(type) {
+		case nil:
+			created := newTable()
+			created.dotted = true
+			t.values[name] = created
+			t = created
+		case *table:
+			if !next.dotted {
+				return p.errorf("table %s can not be extended with dotted keys", Key(key[:i+1]))
+			}
+
+			t = next
+		default:
+			return p.errorf("key %s is already defined", Key(key[:i+1]))
+		}
+	}
+
+	name := key[len(key)-1]
+
+	if _, ok := t.values[name]; ok {
+		return p.errorf("key %s is already defined", Key(key))
+	}
+
+	t.values[name] = value
+
+	return nil
+}
+
+func (p *parser) key() (Key, error) {
+	var key Key
+
+	for {
+		name, err := p.simpleKey()
+		if err != nil {
+			return nil, err
+		}
+
+		key = append(key, name)
+
+		p.skipSpaces()
+
+		if p.peek() != '.' {
+			return key, nil
+		}
+
+		p.pos++
+		p.skipSpaces()
+	}
+}
+
+func (p *parser) simpleKey() (string, error) {
+	switch {
+	case p.hasPrefix(`"""`) || p.hasPrefix("'''"):
+		return "", p.errorf("multi-line strings are not allowed in keys")
+	case p.peek() == '"':
+		return p.basicString()
+	case p.peek() == '\'':
+		return p.literalString()
+	}
+
+	start := p.pos
+
+	for !p.eof() && isBareKeyChar(p.peek()) {
+		p.pos++
+	}
+
+	if start == p.pos {
+		return "", p.errorf("expected key, got %q", p.peek())
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *parser) value() (any, error) {
+	switch c := p.peek(); {
+	case p.hasPrefix(`"""`):
+		return p.multilineBasicString()
+	case p.hasPrefix("'''"):
+		return p.multilineLiteralString()
+	case c == '"':
+		return p.basicString()
+	case c == '\'':
+		return p.literalString()
+	case p.hasPrefix("true"):
+		p.pos += 4
+
+		return true, nil
+	case p.hasPrefix("false"):
+		p.pos += 5
+
+		return false, nil
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	case p.eof() || c == '\n' || c == '\r' || c == '#':
+		return nil, p.errorf("expected value")
+	default:
+		return p.scalar()
+	}
+}
+
+func (p *parser) array() (any, error) {
+	p.pos++
+
+	items := []any{}
+
+	for {
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+
+		if p.peek() == ']' {
+			p.pos++
+
+			return items, nil
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, value)
+
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+
+			return items, nil
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *parser) inlineTable() (any, error) {
+	p.pos++
+
+	t := newTable()
+
+	p.skipSpaces()
+
+	if p.peek() == '}' {
+		p.pos++
+		t.inline = true
+
+		return t, nil
+	}
+
+	for {
+		p.skipSpaces()
+
+		if err := p.inlineKeyValue(t); err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+
+			freeze(t)
+
+			return t, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// freeze marks the inline table and its dotted sub-tables as not extendable.
+func freeze(t *table) {
+	t.inline = true
+	t.dotted = false
+
+	for _, v := range t.values {
+		if sub, ok := v.(*table); ok {
+			freeze(sub)
+		}
+	}
+}
+
+func (p *parser) basicString() (string, error) {
+	p.pos++
+
+	var b strings.Builder
+
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.peek()
+
+		switch {
+		case c == '"':
+			p.pos++
+
+			return b.String(), nil
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		case c == '\n' || c == '\r':
+			return "", p.errorf("newline in basic string")
+		default:
+			if err := p.char(&b); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+func (p *parser) multilineBasicString() (string, error) {
+	p.pos += 3
+	p.newline()
+
+	var b strings.Builder
+
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+
+		switch c := p.peek(); {
+		case p.hasPrefix(`"""`):
+			if err := p.closeMultiline(&b, `"`); err != nil {
+				return "", err
+			}
+
+			return b.String(), nil
+		case c == '\\':
+			// line ending backslash
+			rest := strings.TrimLeft(p.input[p.pos+1:], " \t")
+
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				p.pos = len(p.input) - len(rest)
+
+				for p.newline() || p.peek() == ' ' || p.peek() == '\t' {
+					p.skipSpaces()
+				}
+
+				continue
+			}
+
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		case p.newline():
+			b.WriteByte('\n')
+		default:
+			if err := p.char(&b); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+func (p *parser) literalString() (string, error) {
+	p.pos++
+
+	start := p.pos
+
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated literal string")
+		}
+
+		c := p.peek()
+
+		switch {
+		case c == '\'':
+			s := p.input[start:p.pos]
+			p.pos++
+
+			return s, nil
+		case c == '\n' || c == '\r':
+			return "", p.errorf("newline in literal string")
+		case isControl(rune(c)) && c != '\t':
+			return "", p.errorf("control character in string")
+		}
+
+		p.pos++
+	}
+}
+
+func (p *parser) multilineLiteralString() (string, error) {
+	p.pos += 3
+	p.newline()
+
+	var b strings.Builder
+
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line literal string")
+		}
+
+		switch {
+		case p.hasPrefix("'''"):
+			if err := p.closeMultiline(&b, "'"); err != nil {
+				return "", err
+			}
+
+			return b.String(), nil
+		case p.newline():
+			b.WriteByte('\n')
+		default:
+			if err := p.char(&b); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+// closeMultiline consumes the closing delimiter of multi-line strings,
+// which may be preceded by up to two quotes belonging to the content.
+func (p *parser) closeMultiline(b *strings.Builder, quote string) error {
+	n := 0
+
+	for strings.HasPrefix(p.input[p.pos+n:], quote) {
+		n++
+	}
+
+	if n > 5 {
+		return p.errorf("too many quotes in multi-line string")
+	}
+
+	b.WriteString(strings.Repeat(quote, n-3))
+	p.pos += n
+
+	return nil
+}
+
+// char writes the next character of a string validating it.
+func (p *parser) char(b *strings.Builder) error {
+	r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+
+	if isControl(r) && r != '\t' {
+		return p.errorf("control character in string")
+	}
+
+	b.WriteRune(r)
+	p.pos += size
+
+	return nil
+}
+
+func (p *parser) escape(b *strings.Builder) error {
+	p.pos++
+
+	if p.eof() {
+		return p.errorf("unterminated escape sequence")
+	}
+
+	c := p.peek()
+	p.pos++
+
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+
+		if len(p.input)-p.pos < size {
+			return p.errorf("invalid unicode escape")
+		}
+
+		code, err := strconv.ParseUint(p.input[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		b.WriteRune(rune(code))
+		p.pos += size
+	default:
+		p.pos--
+
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+
+	return nil
+}
+
+var (
+	decimalRe = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	hexRe     = regexp.MustCompile(`^0x[0-9A-Fa-f](_?[0-9A-Fa-f])*$`)
+	octalRe   = regexp.MustCompile(`^0o[0-7](_?[0-7])*$`)
+	binaryRe  = regexp.MustCompile(`^0b[01](_?[01])*$`)
+	floatRe   = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)((\.[0-9](_?[0-9])*)([eE][+-]?[0-9](_?[0-9])*)?|[eE][+-]?[0-9](_?[0-9])*)$`)
+	specialRe = regexp.MustCompile(`^[+-]?(inf|nan)$`)
+
+	dateRe     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	timeRe     = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}(\.\d+)?$`)
+	dateTimeRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})[Tt ](\d{2}:\d{2}:\d{2}(?:\.\d+)?)([Zz]|[+-]\d{2}:\d{2})?$`)
+)
+
+// scalar parses numbers, booleans and datetimes.
+func (p *parser) scalar() (any, error) {
+	start := p.pos
+
+	for !p.eof() && isScalarChar(p.peek()) {
+		p.pos++
+	}
+
+	// date and time may be separated by a space
+	if dateRe.MatchString(p.input[start:p.pos]) && len(p.input)-p.pos > 3 &&
+		p.input[p.pos] == ' ' && isDigit(p.input[p.pos+1]) && isDigit(p.input[p.pos+2]) && p.input[p.pos+3] == ':' {
+		p.pos++
+
+		for !p.eof() && isScalarChar(p.peek()) {
+			p.pos++
+		}
+	}
+
+	token := p.input[start:p.pos]
+
+	if token == "" {
+		return nil, p.errorf("expected value, got %q", p.peek())
+	}
+
+	value, ok := parseScalar(token)
+	if !ok {
+		p.pos = start
+
+		return nil, p.errorf("invalid value %q", token)
+	}
+
+	return value, nil
+}
+
+func parseScalar(token string) (any, bool) {
+	clean := strings.ReplaceAll(token, "_", "")
+
+	switch {
+	case decimalRe.MatchString(token):
+		n, err := strconv.ParseInt(clean, 10, 64)
+
+		return n, err == nil
+	case hexRe.MatchString(token), octalRe.MatchString(token), binaryRe.MatchString(token):
+		n, err := strconv.ParseInt(clean, 0, 64)
+
+		return n, err == nil
+	case floatRe.MatchString(token):
+		f, err := strconv.ParseFloat(clean, 64)
+
+		return f, err == nil
+	case specialRe.MatchString(token):
+		sign := 1.0
+		if token[0] == '-' {
+			sign = -1
+		}
+
+		if strings.HasSuffix(token, "nan") {
+			return math.NaN(), true
+		}
+
+		return math.Inf(int(sign)), true
+	}
+
+	return parseDateTime(token)
+}
+
+func parseDateTime(token string) (any, bool) {
+	switch {
+	case dateRe.MatchString(token):
+		t, err := time.ParseInLocation(time.DateOnly, token, time.Local)
+
+		return t, err == nil
+	case timeRe.MatchString(token):
+		t, err := time.ParseInLocation("15:04:05.999999999", token, time.Local)
+
+		return t, err == nil
+	}
+
+	match := dateTimeRe.FindStringSubmatch(token)
+	if match == nil {
+		return nil, false
+	}
+
+	normalized := match[1] + "T" + match[2]
+
+	if match[3] == "" {
+		t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", normalized, time.Local)
+
+		return t, err == nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, normalized+strings.ToUpper(match[3]))
+
+	return t, err == nil
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c == '_' || c == '-'
+}
+
+func isScalarChar(c byte) bool {
+	return isBareKeyChar(c) || c == '+' || c == '.' || c == ':'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
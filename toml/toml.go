@@ -0,0 +1,465 @@
+// Package toml implements decoding of TOML 1.0 documents into structs with [opt.Opt] fields.
+//
+// Options are set only for the keys present in the document, so that
+// absent keys leave them implicit None, see [opt.Opt.IsExplicit].
+// TOML has no null value, therefore present keys always decode into Some.
+//
+// Mapping between TOML and Go values:
+//   - Tables decode into structs, keyed by `toml:"name"` tags or field names (case-insensitive), and maps with string keys
+//   - Arrays and arrays of tables decode into slices and arrays
+//   - Offset date-times decode into [time.Time], local date-times, dates and times into [time.Time] in [time.Local]
+//   - Strings decode into types implementing [encoding.TextUnmarshaler]
+//   - Integers decode into integer and float types with overflow checks
+//   - Any value decodes into an empty interface as map[string]any, []any, string, int64, float64, bool or [time.Time]
+package toml
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+// ParseError is a syntax or semantic error in the TOML document.
+type ParseError struct {
+	// Line is 1-based line number of the error
+	Line int
+
+	// Column is 1-based column number of the error in characters
+	Column int
+
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("toml: line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// DecodeError is an error of decoding a TOML value into a Go value.
+type DecodeError struct {
+	Key Key
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("toml: key %s: %v", e.Key, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Key is a path of keys to the value.
+type Key []string
+
+// String returns dotted representation of the key, quoting parts if needed.
+func (k Key) String() string {
+	parts := make([]string, len(k))
+
+	for i, part := range k {
+		if part != "" && strings.IndexFunc(part, func(r rune) bool { return r > 0x7f || !isBareKeyChar(byte(r)) }) < 0 {
+			parts[i] = part
+		} else {
+			parts[i] = strconv.Quote(part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// MetaData holds information about the decoded document.
+type MetaData struct {
+	keys      []Key
+	undecoded []Key
+}
+
+// Keys returns all the keys defined in the document, sorted.
+func (m MetaData) Keys() []Key {
+	return m.keys
+}
+
+// IsDefined reports whether the key is defined in the document.
+func (m MetaData) IsDefined(key ...string) bool {
+	return slices.ContainsFunc(m.keys, func(k Key) bool {
+		return slices.Equal(k, key)
+	})
+}
+
+// Undecoded returns the keys which are not mapped to any struct field, sorted.
+//
+// Keys inside values decoded into maps and interfaces are considered decoded.
+func (m MetaData) Undecoded() []Key {
+	return m.undecoded
+}
+
+// Unmarshal decodes the TOML document into the value pointed to by v.
+// Unknown keys are ignored, use [Decode] to report them.
+func Unmarshal(data []byte, v any) error {
+	_, err := Decode(data, v)
+
+	return err
+}
+
+// Decode decodes the TOML document into the value pointed to by v,
+// returning metadata with the defined and undecoded keys.
+func Decode(data []byte, v any) (MetaData, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return MetaData{}, fmt.Errorf("toml: Decode(non-pointer %T)", v)
+	}
+
+	root, err := parse(string(data))
+	if err != nil {
+		return MetaData{}, err
+	}
+
+	d := decoder{}
+
+	collectKeys(nil, root, &d.meta.keys)
+
+	if err := d.decode(nil, root, rv.Elem()); err != nil {
+		return MetaData{}, err
+	}
+
+	sortKeys(d.meta.keys)
+	sortKeys(d.meta.undecoded)
+
+	return d.meta, nil
+}
+
+func sortKeys(keys []Key) {
+	slices.SortFunc(keys, func(a, b Key) int {
+		return slices.Compare(a, b)
+	})
+}
+
+func collectKeys(prefix Key, t *table, keys *[]Key) {
+	for name, value := range t.values {
+		key := append(slices.Clone(prefix), name)
+		*keys = append(*keys, key)
+
+		switch value := value.(type) {
+		case *table:
+			collectKeys(key, value, keys)
+		case *tableArray:
+			for _, t := range value.tables {
+				collectKeys(key, t, keys)
+			}
+		}
+	}
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+type decoder struct {
+	meta MetaData
+}
+
+func (d *decoder) decode(key Key, src any, dst reflect.Value) error {
+	if optreflect.Is(dst.Type()) {
+		value := reflect.New(optreflect.Elem(dst.Type())).Elem()
+
+		if err := d.decode(key, src, value); err != nil {
+			return err
+		}
+
+		optreflect.SetSome(dst, value)
+
+		return nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+
+		return d.decode(key, src, dst.Elem())
+	}
+
+	if dst.Kind() == reflect.Interface && dst.NumMethod() == 0 {
+		dst.Set(reflect.ValueOf(plain(src)))
+
+		return nil
+	}
+
+	if s, ok := src.(string); ok && reflect.PointerTo(dst.Type()).Implements(textUnmarshalerType) {
+		if err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return &DecodeError{Key: key, Err: err}
+		}
+
+		return nil
+	}
+
+	typeError := &DecodeError{Key: key, Err: fmt.Errorf("cannot decode %s into %s", describe(src), dst.Type())}
+
+	switch src := src.(type) {
+	case *table:
+		return d.table(key, src, dst, typeError)
+	case *tableArray:
+		items := make([]any, len(src.tables))
+
+		for i, t := range src.tables {
+			items[i] = t
+		}
+
+		return d.array(key, items, dst, typeError)
+	case []any:
+		return d.array(key, src, dst, typeError)
+	case string:
+		if dst.Kind() != reflect.String {
+			return typeError
+		}
+
+		dst.SetString(src)
+	case bool:
+		if dst.Kind() != reflect.Bool {
+			return typeError
+		}
+
+		dst.SetBool(src)
+	case int64:
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if dst.OverflowInt(src) {
+				return &DecodeError{Key: key, Err: fmt.Errorf("%d overflows %s", src, dst.Type())}
+			}
+
+			dst.SetInt(src)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if src < 0 || dst.OverflowUint(uint64(src)) {
+				return &DecodeError{Key: key, Err: fmt.Errorf("%d overflows %s", src, dst.Type())}
+			}
+
+			dst.SetUint(uint64(src))
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(float64(src))
+		default:
+			return typeError
+		}
+	case float64:
+		if dst.Kind() != reflect.Float32 && dst.Kind() != reflect.Float64 {
+			return typeError
+		}
+
+		dst.SetFloat(src)
+	case time.Time:
+		if dst.Type() != timeType {
+			return typeError
+		}
+
+		dst.Set(reflect.ValueOf(src))
+	default:
+		return typeError
+	}
+
+	return nil
+}
+
+func (d *decoder) table(key Key, src *table, dst reflect.Value, typeError error) error {
+	switch dst.Kind() {
+	case reflect.Map:
+		if dst.Type().Key().Kind() != reflect.String {
+			return typeError
+		}
+
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+
+		for name, value := range src.values {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+
+			if err := d.decode(append(slices.Clone(key), name), value, elem); err != nil {
+				return err
+			}
+
+			dst.SetMapIndex(reflect.ValueOf(name).Convert(dst.Type().Key()), elem)
+		}
+
+		return nil
+	case reflect.Struct:
+		fields := structFields(dst.Type())
+
+		for name, value := range src.values {
+			fieldKey := append(slices.Clone(key), name)
+
+			f, ok := fields.lookup(name)
+			if !ok {
+				d.undecoded(fieldKey, value)
+
+				continue
+			}
+
+			field, err := dst.FieldByIndexErr(f.index)
+			if err != nil {
+				return &DecodeError{Key: fieldKey, Err: err}
+			}
+
+			if err := d.decode(fieldKey, value, field); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return typeError
+	}
+}
+
+// undecoded records the key and all of its nested keys as undecoded.
+func (d *decoder) undecoded(key Key, value any) {
+	d.meta.undecoded = append(d.meta.undecoded, key)
+
+	switch value := value.(type) {
+	case *table:
+		collectKeys(key, value, &d.meta.undecoded)
+	case *tableArray:
+		for _, t := range value.tables {
+			collectKeys(key, t, &d.meta.undecoded)
+		}
+	}
+}
+
+func (d *decoder) array(key Key, items []any, dst reflect.Value, typeError error) error {
+	switch dst.Kind() {
+	case reflect.Slice:
+		dst.Set(reflect.MakeSlice(dst.Type(), len(items), len(items)))
+	case reflect.Array:
+		if len(items) > dst.Len() {
+			return &DecodeError{Key: key, Err: fmt.Errorf("array of %d items does not fit into %s", len(items), dst.Type())}
+		}
+
+		dst.SetZero()
+	default:
+		return typeError
+	}
+
+	for i, item := range items {
+		if err := d.decode(key, item, dst.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// plain converts parsed value into a generic Go value.
+func plain(src any) any {
+	switch src := src.(type) {
+	case *table:
+		m := make(map[string]any, len(src.values))
+
+		for name, value := range src.values {
+			m[name] = plain(value)
+		}
+
+		return m
+	case *tableArray:
+		items := make([]any, len(src.tables))
+
+		for i, t := range src.tables {
+			items[i] = plain(t)
+		}
+
+		return items
+	case []any:
+		items := make([]any, len(src))
+
+		for i, item := range src {
+			items[i] = plain(item)
+		}
+
+		return items
+	default:
+		return src
+	}
+}
+
+func describe(src any) string {
+	switch src.(type) {
+	case *table:
+		return "table"
+	case *tableArray, []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case time.Time:
+		return "datetime"
+	default:
+		return fmt.Sprintf("%T", src)
+	}
+}
+
+type field struct {
+	name  string
+	index []int
+}
+
+type fields []field
+
+// lookup finds the field by exact name, falling back to case-insensitive match.
+func (fs fields) lookup(name string) (field, bool) {
+	for _, f := range fs {
+		if f.name == name {
+			return f, true
+		}
+	}
+
+	for _, f := range fs {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
+
+func structFields(t reflect.Type) fields {
+	var result fields
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, tagged := f.Tag.Lookup("toml")
+		name, _, _ = strings.Cut(name, ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct && !optreflect.Is(f.Type) && f.Type != timeType {
+			for _, embedded := range structFields(f.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				result = append(result, embedded)
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		result = append(result, field{name: name, index: []int{i}})
+	}
+
+	return result
+}
@@ -0,0 +1,210 @@
+package toml
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/metafates/opt"
+	"github.com/stretchr/testify/require"
+)
+
+type Server struct {
+	Host    opt.Opt[netip.Addr] `toml:"host"`
+	Port    opt.Opt[int]        `toml:"port"`
+	Enabled opt.Opt[bool]       `toml:"enabled"`
+}
+
+type Product struct {
+	Name  string          `toml:"name"`
+	SKU   opt.Opt[uint32] `toml:"sku"`
+	Color opt.Opt[string] `toml:"color"`
+}
+
+type Config struct {
+	Title    opt.Opt[string]    `toml:"title"`
+	Released opt.Opt[time.Time] `toml:"released"`
+	Updated  opt.Opt[time.Time] `toml:"updated"`
+	Ratio    opt.Opt[float64]   `toml:"ratio"`
+	Tags     opt.Opt[[]string]  `toml:"tags"`
+	Server   opt.Opt[Server]    `toml:"server"`
+	Backup   *Server            `toml:"backup"`
+	Products []Product          `toml:"products"`
+	Extra    map[string]any     `toml:"extra"`
+}
+
+const document = `
+# This is a TOML document
+title = "TOML \"Example\""
+released = 1979-05-27T07:32:00-08:00
+updated = 1979-05-27
+ratio = 1_000.5e-3
+tags = [
+  "a", # comment
+  'b',
+]
+unknown = 1
+
+[server]
+host = "192.168.1.1"
+port = 8_000
+
+[backup]
+port = 0x1F
+
+[[products]]
+name = "Hammer"
+sku = 738594937
+
+[[products]]
+
+[[products]]
+name = "Nail"
+sku = 284758393
+color = """
+gray\
+  """
+
+[extra]
+nested.key = { a = 1, b = [1.5, true] }
+`
+
+func TestDecode(t *testing.T) {
+	var config Config
+
+	meta, err := Decode([]byte(document), &config)
+	require.NoError(t, err)
+
+	require.Equal(t, opt.Some(`TOML "Example"`), config.Title)
+	require.True(t, config.Released.MustGet().Equal(time.Date(1979, 5, 27, 15, 32, 0, 0, time.UTC)))
+	require.Equal(t, opt.Some(time.Date(1979, 5, 27, 0, 0, 0, 0, time.Local)), config.Updated)
+	require.Equal(t, opt.Some(1.0005), config.Ratio)
+	require.Equal(t, opt.Some([]string{"a", "b"}), config.Tags)
+
+	require.Equal(t, opt.Some(Server{
+		Host: opt.Some(netip.MustParseAddr("192.168.1.1")),
+		Port: opt.Some(8000),
+	}), config.Server)
+	require.False(t, config.Server.MustGet().Enabled.IsExplicit())
+
+	require.Equal(t, &Server{Port: opt.Some(31)}, config.Backup)
+
+	require.Equal(t, []Product{
+		{Name: "Hammer", SKU: opt.Some[uint32](738594937)},
+		{},
+		{Name: "Nail", SKU: opt.Some[uint32](284758393), Color: opt.Some("gray")},
+	}, config.Products)
+
+	require.Equal(t, map[string]any{
+		"nested": map[string]any{
+			"key": map[string]any{"a": int64(1), "b": []any{1.5, true}},
+		},
+	}, config.Extra)
+
+	require.Equal(t, []Key{{"unknown"}}, meta.Undecoded())
+	require.True(t, meta.IsDefined("server", "port"))
+	require.False(t, meta.IsDefined("server", "enabled"))
+}
+
+func TestUnmarshal_Values(t *testing.T) {
+	testCases := []struct {
+		name string
+		toml string
+		want any
+	}{
+		{"decimal", "v = -17", int64(-17)},
+		{"hex", "v = 0xDEAD_beef", int64(0xdeadbeef)},
+		{"octal", "v = 0o755", int64(0o755)},
+		{"binary", "v = 0b1101", int64(13)},
+		{"float", "v = 6.626e-34", 6.626e-34},
+		{"exponent", "v = 5e+22", 5e+22},
+		{"inf", "v = -inf", math.Inf(-1)},
+		{"literal", `v = 'C:\Users'`, `C:\Users`},
+		{"unicode", `v = "\u00e9\U0001F600"`, "é😀"},
+		{"multiline literal", "v = '''\nfirst\n  second'''", "first\n  second"},
+		{"multiline quotes", `v = """a""""`, `a"`},
+		{"offset datetime", "v = 1979-05-27 07:32:00.999Z", time.Date(1979, 5, 27, 7, 32, 0, 999000000, time.UTC)},
+		{"local datetime", "v = 1979-05-27T07:32:00", time.Date(1979, 5, 27, 7, 32, 0, 0, time.Local)},
+		{"local time", "v = 07:32:00", time.Date(0, 1, 1, 7, 32, 0, 0, time.Local)},
+		{"quoted key", `"v" = 1`, int64(1)},
+		{"mixed array", `v = [1, "a", [2]]`, []any{int64(1), "a", []any{int64(2)}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var v struct {
+				V any `toml:"v"`
+			}
+
+			err := Unmarshal([]byte(tc.toml), &v)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, v.V)
+		})
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		toml string
+	}{
+		{"duplicate key", "a = 1\na = 2"},
+		{"duplicate table", "[a]\n[a]"},
+		{"table after dotted", "a.b = 1\n[a]"},
+		{"dotted into header table", "[a.b]\n[a]\nb.c = 1"},
+		{"extend inline", "a = {b = 1}\n[a.c]"},
+		{"extend inline dotted", "a = {b = 1}\na.c = 2"},
+		{"array of tables over array", "a = []\n[[a]]"},
+		{"table over array of tables", "[[a]]\n[a]"},
+		{"leading zero", "a = 01"},
+		{"bad underscore", "a = 1__0"},
+		{"trailing dot", "a = 1."},
+		{"signed hex", "a = +0x1"},
+		{"no value", "a = "},
+		{"newline in string", "a = \"b\nc\""},
+		{"bad escape", `a = "\q"`},
+		{"inline newline", "a = {b = 1,\nc = 2}"},
+		{"inline trailing comma", "a = {b = 1,}"},
+		{"two values", "a = 1 b = 2"},
+		{"bad date", "a = 2023-02-30"},
+		{"unterminated array", "a = [1, 2"},
+		{"too many quotes", `a = """a"""""""`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var v map[string]any
+
+			err := Unmarshal([]byte(tc.toml), &v)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+		})
+	}
+}
+
+func TestUnmarshal_DecodeError(t *testing.T) {
+	var v struct {
+		Server struct {
+			Port uint8 `toml:"port"`
+		} `toml:"server"`
+	}
+
+	err := Unmarshal([]byte("[server]\nport = 8080"), &v)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "server.port", decodeErr.Key.String())
+}
+
+func TestParseError_Position(t *testing.T) {
+	var v map[string]any
+
+	err := Unmarshal([]byte("a = 1\nb = \"x\" y"), &v)
+
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 2, parseErr.Line)
+	require.Equal(t, 9, parseErr.Column)
+}
@@ -0,0 +1,24 @@
+package opt
+
+import "errors"
+
+// Validate runs the rules against the contained value if the option is [Some]
+// and returns the joined errors of the failed ones.
+//
+// [None], either explicit or implicit, is always valid.
+// See the validate subpackage for the rules and presence checks.
+func Validate[T any](o Opt[T], rules ...func(T) error) error {
+	if !o.hasValue {
+		return nil
+	}
+
+	var errs []error
+
+	for _, rule := range rules {
+		if err := rule(o.value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// Rule validates a value. Rules are passed to [opt.Validate], which runs them only on Some values:
+//
+//	err := opt.Validate(port, validate.Min(1), validate.Max(65535))
+type Rule[T any] func(T) error
+
+// Min checks that the value is greater than or equal to min.
+//
+// T is inferred from min, so untyped constants produce Rule[int] or Rule[float64].
+// Instantiate it explicitly for other types, e.g. Min[int64](1) for opt.Opt[int64].
+func Min[T cmp.Ordered](min T) Rule[T] {
+	return func(value T) error {
+		if value < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+
+		return nil
+	}
+}
+
+// Max checks that the value is less than or equal to max.
+// Like [Min], it needs explicit instantiation for other types than int and float64, e.g. Max[uint8](10).
+func Max[T cmp.Ordered](max T) Rule[T] {
+	return func(value T) error {
+		if value > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+
+		return nil
+	}
+}
+
+// MinLen checks that the string has at least n characters.
+func MinLen[T ~string](n int) Rule[T] {
+	return func(value T) error {
+		if utf8.RuneCountInString(string(value)) < n {
+			return fmt.Errorf("length must be at least %d", n)
+		}
+
+		return nil
+	}
+}
+
+// MaxLen checks that the string has at most n characters.
+func MaxLen[T ~string](n int) Rule[T] {
+	return func(value T) error {
+		if utf8.RuneCountInString(string(value)) > n {
+			return fmt.Errorf("length must be at most %d", n)
+		}
+
+		return nil
+	}
+}
+
+// OneOf checks that the value equals one of the given values.
+func OneOf[T comparable](values ...T) Rule[T] {
+	return func(value T) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("must be one of %v", values)
+		}
+
+		return nil
+	}
+}
+
+// Match checks that the string matches the regular expression.
+func Match[T ~string](re *regexp.Regexp) Rule[T] {
+	return func(value T) error {
+		if !re.MatchString(string(value)) {
+			return fmt.Errorf("must match %s", re)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,463 @@
+// Package validate validates structs with [opt.Opt] fields.
+//
+// Rules are declared with `validate:"..."` tags as a comma-separated list, e.g. `validate:"min=1,max=10"`.
+// Value rules run only on Some values of options, so an absent value is valid unless a presence rule says otherwise:
+//   - min=N, max=N: numbers must be in the range, strings, slices and maps must have the length in the range
+//   - len=N: strings, slices and maps must have exactly N elements
+//   - oneof=a b c: value must be one of the space-separated values
+//
+// Presence rules distinguish explicit None from the missing value, see [opt.Opt.IsExplicit]:
+//   - required: option must be Some, other values must be non-zero
+//   - notnull: option must not be explicit None, but may be missing
+//   - present: option must be explicitly set, either Some or explicit None
+//
+// Cross-field rules refer to other fields of the same struct by their Go names.
+// The field is present if it is Some or, for non-options, non-zero:
+//   - required_if=Other: field is required if Other is present
+//   - requires=Other: Other must be present if the field is
+//   - excludes=Other: Other must not be present if the field is
+//   - one_of_present=group: exactly one field of the group must be present
+//
+// Nested structs, pointers to them and slices of them are validated recursively.
+// Errors are reported as [Errors] with JSON paths of the fields, e.g. items[0].name.
+// As in [encoding/json], fields tagged with `json:"-"` are skipped and embedded structs are flattened.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/metafates/opt/internal/optreflect"
+)
+
+// FieldError is a failed rule of a single field.
+type FieldError struct {
+	// Path is the JSON path of the field, e.g. address.city or items[0].name
+	Path string
+
+	// Rule is the name of the failed rule, e.g. min
+	Rule string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("validate: %s: %v", e.Path, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the error as an object with path, rule and message members.
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path    string `json:"path"`
+		Rule    string `json:"rule"`
+		Message string `json:"message"`
+	}{
+		Path:    e.Path,
+		Rule:    e.Rule,
+		Message: e.Err.Error(),
+	})
+}
+
+// Errors is a list of field errors.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Struct validates the struct (or a pointer to it) according to the `validate` tags.
+//
+// Failed rules are reported together as [Errors].
+// Malformed tags, e.g. unknown rules, are reported as a plain error.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected struct, got %T", v)
+	}
+
+	var errs Errors
+
+	if err := validateStruct(rv, "", &errs); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+type field struct {
+	goName string
+	path   string
+	index  []int
+	rules  []rule
+}
+
+func parseFields(t reflect.Type, prefix string) ([]field, error) {
+	fields := make([]field, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		// embedded structs without names are flattened, as encoding/json does
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct && f.Type != timeType && !optreflect.Is(f.Type) {
+			embedded, err := parseFields(f.Type, prefix)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, e := range embedded {
+				e.index = append([]int{i}, e.index...)
+				fields = append(fields, e)
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		var rules []rule
+
+		if tag := f.Tag.Get("validate"); tag != "" {
+			for _, r := range strings.Split(tag, ",") {
+				ruleName, param, _ := strings.Cut(strings.TrimSpace(r), "=")
+
+				if !slices.Contains(knownRules, ruleName) {
+					return nil, fmt.Errorf("validate: field %s: unknown rule %q", f.Name, ruleName)
+				}
+
+				rules = append(rules, rule{name: ruleName, param: param})
+			}
+		}
+
+		fields = append(fields, field{goName: f.Name, path: name, index: f.Index, rules: rules})
+	}
+
+	return fields, nil
+}
+
+var knownRules = []string{
+	"min", "max", "len", "oneof",
+	"required", "notnull", "present",
+	"required_if", "requires", "excludes", "one_of_present",
+}
+
+func validateStruct(v reflect.Value, prefix string, errs *Errors) error {
+	fields, err := parseFields(v.Type(), prefix)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]field, len(fields))
+
+	for _, f := range fields {
+		byName[f.goName] = f
+	}
+
+	groups := make(map[string][]field)
+
+	for _, f := range fields {
+		value := v.FieldByIndex(f.index)
+
+		for _, r := range f.rules {
+			if r.name == "one_of_present" {
+				groups[r.param] = append(groups[r.param], f)
+
+				continue
+			}
+
+			err := checkRule(r, value, func(name string) (reflect.Value, string, error) {
+				other, ok := byName[name]
+				if !ok {
+					return reflect.Value{}, "", fmt.Errorf("validate: field %s: rule %s refers to unknown field %q", f.goName, r.name, name)
+				}
+
+				return v.FieldByIndex(other.index), other.path, nil
+			})
+
+			var fieldErr *FieldError
+
+			switch {
+			case errors.As(err, &fieldErr):
+				fieldErr.Path = f.path
+				*errs = append(*errs, fieldErr)
+			case err != nil:
+				return err
+			}
+		}
+
+		if err := validateNested(value, f.path, errs); err != nil {
+			return err
+		}
+	}
+
+	for _, group := range sortedKeys(groups) {
+		members := groups[group]
+
+		var present, paths []string
+
+		for _, f := range members {
+			paths = append(paths, f.path)
+
+			if isPresent(v.FieldByIndex(f.index)) {
+				present = append(present, f.path)
+			}
+		}
+
+		if len(present) != 1 {
+			*errs = append(*errs, &FieldError{
+				Path: members[0].path,
+				Rule: "one_of_present",
+				Err:  fmt.Errorf("exactly one of %s must be present, got %d", strings.Join(paths, ", "), len(present)),
+			})
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func validateNested(v reflect.Value, path string, errs *Errors) error {
+	if optreflect.Is(v.Type()) {
+		inner, ok := optreflect.Get(v)
+		if !ok {
+			return nil
+		}
+
+		v = inner
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+
+		return validateNested(v.Elem(), path, errs)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return nil
+		}
+
+		return validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateNested(v.Index(i), path+"["+strconv.Itoa(i)+"]", errs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isPresent reports whether the option is Some or the other value is non-zero.
+func isPresent(v reflect.Value) bool {
+	if optreflect.Is(v.Type()) {
+		_, ok := optreflect.Get(v)
+
+		return ok
+	}
+
+	return !v.IsZero()
+}
+
+// checkRule checks the rule against the field value. Failed rules are returned as [FieldError] without path.
+func checkRule(r rule, v reflect.Value, lookup func(name string) (reflect.Value, string, error)) error {
+	fail := func(format string, args ...any) error {
+		return &FieldError{Rule: r.name, Err: fmt.Errorf(format, args...)}
+	}
+
+	isOpt := optreflect.Is(v.Type())
+
+	switch r.name {
+	case "required":
+		return checkRequired(v, fail)
+	case "notnull":
+		if isOpt && optreflect.IsExplicit(v) && !isPresent(v) {
+			return fail("must not be null")
+		}
+
+		return nil
+	case "present":
+		if isOpt && !optreflect.IsExplicit(v) {
+			return fail("must be present")
+		}
+
+		return nil
+	case "required_if", "requires", "excludes":
+		other, otherPath, err := lookup(r.param)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case r.name == "required_if" && isPresent(other):
+			return checkRequired(v, func(format string, args ...any) error {
+				return fail(format+" when %s is present", append(args, otherPath)...)
+			})
+		case r.name == "requires" && isPresent(v) && !isPresent(other):
+			return fail("requires %s to be present", otherPath)
+		case r.name == "excludes" && isPresent(v) && isPresent(other):
+			return fail("must not be present together with %s", otherPath)
+		}
+
+		return nil
+	}
+
+	// value rules
+	if isOpt {
+		inner, ok := optreflect.Get(v)
+		if !ok {
+			return nil
+		}
+
+		v = inner
+	}
+
+	switch r.name {
+	case "min", "max", "len":
+		return checkBound(r, v, fail)
+	case "oneof":
+		options := strings.Fields(r.param)
+
+		if !slices.Contains(options, fmt.Sprint(v.Interface())) {
+			return fail("must be one of %s", strings.Join(options, ", "))
+		}
+	}
+
+	return nil
+}
+
+func checkRequired(v reflect.Value, fail func(format string, args ...any) error) error {
+	switch {
+	case isPresent(v):
+		return nil
+	case optreflect.Is(v.Type()) && optreflect.IsExplicit(v):
+		return fail("must not be null")
+	default:
+		return fail("is required")
+	}
+}
+
+func checkBound(r rule, v reflect.Value, fail func(format string, args ...any) error) error {
+	var (
+		actual float64
+		isLen  bool
+	)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		actual = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = v.Float()
+	case reflect.String:
+		actual, isLen = float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual, isLen = float64(v.Len()), true
+	default:
+		return fmt.Errorf("validate: rule %s is not supported for %s", r.name, v.Type())
+	}
+
+	bound, err := strconv.ParseFloat(r.param, 64)
+	if err != nil {
+		return fmt.Errorf("validate: rule %s: invalid parameter %q", r.name, r.param)
+	}
+
+	if r.name == "len" {
+		if !isLen {
+			return fmt.Errorf("validate: rule len is not supported for %s", v.Type())
+		}
+
+		if actual != bound {
+			return fail("length must be %s", r.param)
+		}
+
+		return nil
+	}
+
+	subject := "must"
+	if isLen {
+		subject = "length must"
+	}
+
+	switch {
+	case r.name == "min" && actual < bound:
+		return fail("%s be at least %s", subject, r.param)
+	case r.name == "max" && actual > bound:
+		return fail("%s be at most %s", subject, r.param)
+	}
+
+	return nil
+}
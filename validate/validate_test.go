@@ -0,0 +1,240 @@
+package validate
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metafates/opt"
+)
+
+type Item struct {
+	Name     string       `json:"name" validate:"required,max=5"`
+	Quantity opt.Opt[int] `json:"quantity" validate:"min=1"`
+}
+
+type Order struct {
+	ID       int               `json:"id" validate:"required"`
+	Note     opt.Opt[string]   `json:"note" validate:"notnull,max=10"`
+	Status   opt.Opt[string]   `json:"status" validate:"present,oneof=new paid"`
+	Items    []Item            `json:"items" validate:"min=1"`
+	Coupon   opt.Opt[string]   `json:"coupon" validate:"excludes=Discount"`
+	Discount opt.Opt[int]      `json:"discount" validate:"requires=Reason"`
+	Reason   opt.Opt[string]   `json:"reason"`
+	Shipping opt.Opt[bool]     `json:"shipping"`
+	Address  opt.Opt[*Address] `json:"address" validate:"required_if=Shipping"`
+	Phone    opt.Opt[string]   `json:"phone" validate:"one_of_present=contact"`
+	Email    opt.Opt[string]   `json:"email" validate:"one_of_present=contact"`
+}
+
+type Address struct {
+	City string `json:"city" validate:"len=3"`
+}
+
+func valid() Order {
+	return Order{
+		ID:     1,
+		Status: opt.Some("new"),
+		Items:  []Item{{Name: "a"}},
+		Phone:  opt.Some("123"),
+	}
+}
+
+func TestStruct(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(o *Order)
+		want   map[string]string
+	}{
+		{
+			name:   "valid",
+			modify: func(*Order) {},
+		},
+		{
+			name: "valid with all fields",
+			modify: func(o *Order) {
+				o.Note = opt.Some("note")
+				o.Discount = opt.Some(10)
+				o.Reason = opt.Some("loyalty")
+				o.Shipping = opt.Some(true)
+				o.Address = opt.Some(&Address{City: "Rio"})
+				o.Items = append(o.Items, Item{Name: "b", Quantity: opt.Some(2)})
+			},
+		},
+		{
+			name: "required",
+			modify: func(o *Order) {
+				o.ID = 0
+				o.Items[0].Name = ""
+			},
+			want: map[string]string{"id": "is required", "items[0].name": "is required"},
+		},
+		{
+			name:   "notnull",
+			modify: func(o *Order) { o.Note = opt.None[string]() },
+			want:   map[string]string{"note": "must not be null"},
+		},
+		{
+			name:   "present",
+			modify: func(o *Order) { o.Status = opt.Opt[string]{} },
+			want:   map[string]string{"status": "must be present"},
+		},
+		{
+			name:   "explicit None is present",
+			modify: func(o *Order) { o.Status = opt.None[string]() },
+		},
+		{
+			name: "value rules",
+			modify: func(o *Order) {
+				o.Note = opt.Some("a very long note")
+				o.Status = opt.Some("shipped")
+				o.Items = []Item{{Name: "abcdef", Quantity: opt.Some(0)}}
+			},
+			want: map[string]string{
+				"note":              "length must be at most 10",
+				"status":            "must be one of new, paid",
+				"items[0].name":     "length must be at most 5",
+				"items[0].quantity": "must be at least 1",
+			},
+		},
+		{
+			name:   "slice length",
+			modify: func(o *Order) { o.Items = nil },
+			want:   map[string]string{"items": "length must be at least 1"},
+		},
+		{
+			name: "requires and excludes",
+			modify: func(o *Order) {
+				o.Coupon = opt.Some("SALE")
+				o.Discount = opt.Some(10)
+			},
+			want: map[string]string{
+				"coupon":   "must not be present together with discount",
+				"discount": "requires reason to be present",
+			},
+		},
+		{
+			name:   "required if",
+			modify: func(o *Order) { o.Shipping = opt.Some(false) },
+			want:   map[string]string{"address": "is required when shipping is present"},
+		},
+		{
+			name: "nested",
+			modify: func(o *Order) {
+				o.Shipping = opt.Some(true)
+				o.Address = opt.Some(&Address{City: "Paris"})
+			},
+			want: map[string]string{"address.city": "length must be 3"},
+		},
+		{
+			name:   "one of present",
+			modify: func(o *Order) { o.Email = opt.Some("a@b.c") },
+			want:   map[string]string{"phone": "exactly one of phone, email must be present, got 2"},
+		},
+		{
+			name:   "none of present",
+			modify: func(o *Order) { o.Phone = opt.None[string]() },
+			want:   map[string]string{"phone": "exactly one of phone, email must be present, got 0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := valid()
+			tc.modify(&order)
+
+			err := Struct(&order)
+
+			if tc.want == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var errs Errors
+			require.ErrorAs(t, err, &errs)
+
+			actual := make(map[string]string, len(errs))
+
+			for _, fieldErr := range errs {
+				actual[fieldErr.Path] = fieldErr.Err.Error()
+			}
+
+			require.Equal(t, tc.want, actual)
+		})
+	}
+}
+
+func TestStruct_Embedded(t *testing.T) {
+	type inner struct {
+		X opt.Opt[int] `json:"x" validate:"min=1"`
+	}
+
+	type Outer struct {
+		inner
+
+		Secret opt.Opt[string] `json:"-" validate:"present"`
+		Y      opt.Opt[int]    `json:"y" validate:"requires=X"`
+	}
+
+	err := Struct(Outer{inner: inner{X: opt.Some(0)}})
+
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 1)
+	require.Equal(t, "x", errs[0].Path)
+
+	err = Struct(Outer{Y: opt.Some(1)})
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 1)
+	require.Equal(t, "y", errs[0].Path)
+	require.EqualError(t, errs[0], "validate: y: requires x to be present")
+}
+
+func TestStruct_Malformed(t *testing.T) {
+	require.ErrorContains(t, Struct(struct {
+		A int `validate:"unknown"`
+	}{}), `unknown rule "unknown"`)
+
+	require.ErrorContains(t, Struct(struct {
+		A int `validate:"requires=B"`
+	}{A: 1}), `unknown field "B"`)
+
+	require.ErrorContains(t, Struct(struct {
+		A bool `validate:"min=1"`
+	}{}), "not supported")
+
+	require.ErrorContains(t, Struct(struct {
+		A int `validate:"min=x"`
+	}{}), "invalid parameter")
+
+	require.Error(t, Struct(1))
+}
+
+func TestFieldError_MarshalJSON(t *testing.T) {
+	err := Struct(Item{Name: "abcdef"})
+
+	data, jsonErr := json.Marshal(err)
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `[{"path":"name","rule":"max","message":"length must be at most 5"}]`, string(data))
+}
+
+func TestRules(t *testing.T) {
+	require.NoError(t, opt.Validate(opt.Some(5), Min(1), Max(10)))
+	require.NoError(t, opt.Validate(opt.None[int](), Min(1)))
+	require.NoError(t, opt.Validate(opt.Opt[int]{}, Min(1)))
+
+	err := opt.Validate(opt.Some(0), Min(1), Max(-1))
+	require.EqualError(t, err, "must be at least 1\nmust be at most -1")
+
+	require.NoError(t, opt.Validate(opt.Some(int64(5)), Min[int64](1), Max[int64](10)))
+	require.EqualError(t, opt.Validate(opt.Some(0.5), Min(1.0)), "must be at least 1")
+	require.EqualError(t, opt.Validate(opt.Some(uint8(20)), Max[uint8](10)), "must be at most 10")
+
+	require.EqualError(t, opt.Validate(opt.Some("ab"), MinLen[string](3)), "length must be at least 3")
+	require.EqualError(t, opt.Validate(opt.Some("abcd"), MaxLen[string](3)), "length must be at most 3")
+	require.EqualError(t, opt.Validate(opt.Some("c"), OneOf("a", "b")), "must be one of [a b]")
+	require.EqualError(t, opt.Validate(opt.Some("x1"), Match[string](regexp.MustCompile(`^[a-z]+$`))), "must match ^[a-z]+$")
+}